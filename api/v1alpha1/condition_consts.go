@@ -0,0 +1,102 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+)
+
+// Conditions and condition reasons for NutanixIPPool.
+const (
+	// NutanixIPPoolReadyCondition is true if the pool can be used to allocate IPs, i.e. all of
+	// PrismCentralReachable, CredentialsValid, ClusterResolved and SubnetResolved are true.
+	NutanixIPPoolReadyCondition = clusterv1.ReadyCondition
+)
+
+const (
+	// NutanixIPPoolPrismCentralReachableCondition is true if Prism Central responded to the last request made
+	// by the pool controller.
+	NutanixIPPoolPrismCentralReachableCondition = "PrismCentralReachable"
+
+	// NutanixIPPoolPrismCentralReachableReason surfaces when Prism Central is reachable.
+	NutanixIPPoolPrismCentralReachableReason = "Reachable"
+
+	// NutanixIPPoolPrismCentralUnreachableReason surfaces when Prism Central could not be reached. This reason is
+	// also used for conditions that could not be checked because Prism Central is unreachable.
+	NutanixIPPoolPrismCentralUnreachableReason = "PrismCentralUnreachable"
+
+	// NutanixIPPoolPrismCentralCircuitOpenReason surfaces when requests to Prism Central are not sent because it
+	// failed repeatedly, until a probe request succeeds. This reason is also used for conditions that could not be
+	// checked because the requests were not sent.
+	NutanixIPPoolPrismCentralCircuitOpenReason = "PrismCentralCircuitOpen"
+)
+
+const (
+	// NutanixIPPoolCredentialsValidCondition is true if the credentials referenced by the pool were accepted
+	// by Prism Central.
+	NutanixIPPoolCredentialsValidCondition = "CredentialsValid"
+
+	// NutanixIPPoolCredentialsValidReason surfaces when the credentials were accepted by Prism Central.
+	NutanixIPPoolCredentialsValidReason = "Valid"
+
+	// NutanixIPPoolCredentialsInvalidReason surfaces when the credentials could not be read or were rejected by
+	// Prism Central. This reason is also used for conditions that could not be checked because of invalid
+	// credentials.
+	NutanixIPPoolCredentialsInvalidReason = "CredentialsInvalid"
+)
+
+const (
+	// NutanixIPPoolClusterResolvedCondition is true if the cluster in the pool spec was resolved, or if the pool
+	// spec does not specify a cluster.
+	NutanixIPPoolClusterResolvedCondition = "ClusterResolved"
+
+	// NutanixIPPoolClusterResolvedReason surfaces when the cluster in the pool spec was resolved.
+	NutanixIPPoolClusterResolvedReason = "Resolved"
+
+	// NutanixIPPoolClusterNotRequiredReason surfaces when the pool spec does not specify a cluster.
+	NutanixIPPoolClusterNotRequiredReason = "NotRequired"
+
+	// NutanixIPPoolClusterResolutionFailedReason surfaces when the cluster in the pool spec could not be resolved.
+	NutanixIPPoolClusterResolutionFailedReason = "ClusterResolutionFailed"
+)
+
+const (
+	// NutanixIPPoolSubnetResolvedCondition is true if the subnet in the pool spec was resolved.
+	NutanixIPPoolSubnetResolvedCondition = "SubnetResolved"
+
+	// NutanixIPPoolSubnetResolvedReason surfaces when the subnet in the pool spec was resolved.
+	NutanixIPPoolSubnetResolvedReason = "Resolved"
+
+	// NutanixIPPoolSubnetResolutionFailedReason surfaces when the subnet in the pool spec could not be resolved.
+	NutanixIPPoolSubnetResolutionFailedReason = "SubnetResolutionFailed"
+)
+
+const (
+	// NutanixIPPoolFallbackSubnetsResolvedCondition is true if all fallback subnets in the pool spec were resolved.
+	// It is not part of the Ready condition, as claims are still allocated IPs from the subnet of the pool and from
+	// the fallback subnets that were resolved.
+	NutanixIPPoolFallbackSubnetsResolvedCondition = "FallbackSubnetsResolved"
+
+	// NutanixIPPoolFallbackSubnetsResolvedReason surfaces when all fallback subnets in the pool spec were resolved.
+	NutanixIPPoolFallbackSubnetsResolvedReason = "Resolved"
+
+	// NutanixIPPoolFallbackSubnetsNotRequiredReason surfaces when the pool spec has no fallback subnets.
+	NutanixIPPoolFallbackSubnetsNotRequiredReason = "NotRequired"
+
+	// NutanixIPPoolFallbackSubnetResolutionFailedReason surfaces when a fallback subnet in the pool spec could not
+	// be resolved.
+	NutanixIPPoolFallbackSubnetResolutionFailedReason = "FallbackSubnetResolutionFailed"
+)
+
+const (
+	// NutanixIPPoolDeletingCondition surfaces details about the ongoing deletion of the pool.
+	NutanixIPPoolDeletingCondition = clusterv1.DeletingCondition
+
+	// NutanixIPPoolNotDeletingReason surfaces when the pool is not being deleted.
+	NutanixIPPoolNotDeletingReason = clusterv1.NotDeletingReason
+
+	// NutanixIPPoolDeletingWaitingForIPAddressesReason surfaces when the deletion of the pool is blocked by
+	// IPAddresses that were allocated from the pool and have not been deleted yet.
+	NutanixIPPoolDeletingWaitingForIPAddressesReason = "WaitingForIPAddresses"
+)
@@ -0,0 +1,77 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GlobalNutanixIPPoolKind is the kind for GlobalNutanixIPPool objects.
+	GlobalNutanixIPPoolKind = "GlobalNutanixIPPool"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=cluster-api
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the pool can be used to allocate IPs"
+// +kubebuilder:printcolumn:name="Subnet",type="string",JSONPath=".spec.subnet",description="Subnet to allocate IPs from"
+// +kubebuilder:printcolumn:name="IPv6 Subnet",type="string",JSONPath=".spec.ipv6Subnet",description="Optional subnet to allocate IPv6 IPs from",priority=1
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster",description="Optional PE Cluster to allocate IPs from (only required if Subnet is a name rather than a uuid)"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.ipAddresses.total",description="Total number of IPs in the subnet's IP pools"
+// +kubebuilder:printcolumn:name="Used",type="integer",JSONPath=".status.ipAddresses.used",description="Number of IPs in use in the subnet"
+// +kubebuilder:printcolumn:name="Free",type="integer",JSONPath=".status.ipAddresses.free",description="Number of IPs available for allocation in the subnet"
+// +kubebuilder:printcolumn:name="Reserved",type="integer",JSONPath=".status.ipAddresses.reserved",description="Number of IPs reserved by this provider for claims referencing this pool"
+// +kubebuilder:printcolumn:name="Subnet ExtID",type="string",JSONPath=".status.subnet.extID",description="Resolved subnet extID",priority=1
+// +kubebuilder:printcolumn:name="Prefix",type="integer",JSONPath=".status.subnet.prefix",description="Resolved subnet prefix length",priority=1
+// +kubebuilder:printcolumn:name="Cluster ExtID",type="string",JSONPath=".status.clusterExtID",description="Resolved PE cluster extID",priority=1
+
+// GlobalNutanixIPPool is the Schema for the global nutanixippools API.
+// This pool type is cluster scoped and can be referenced by IPAddressClaims in any namespace. As a result, the
+// credentials secret and trust bundle configmap references must specify a namespace.
+// +kubebuilder:validation:XValidation:message="credentialsSecretRef.namespace is required",rule="has(self.spec.prismCentral.credentialsSecretRef.__namespace__) && self.spec.prismCentral.credentialsSecretRef.__namespace__.size() > 0"
+// +kubebuilder:validation:XValidation:message="trustBundleConfigMapRef.namespace is required",rule="!has(self.spec.prismCentral.additionalTrustBundle) || !has(self.spec.prismCentral.additionalTrustBundle.trustBundleConfigMapRef) || (has(self.spec.prismCentral.additionalTrustBundle.trustBundleConfigMapRef.__namespace__) && self.spec.prismCentral.additionalTrustBundle.trustBundleConfigMapRef.__namespace__.size() > 0)"
+type GlobalNutanixIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NutanixIPPoolSpec   `json:"spec,omitempty"`
+	Status NutanixIPPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GlobalNutanixIPPoolList contains a list of GlobalNutanixIPPool.
+type GlobalNutanixIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalNutanixIPPool `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Idiomatic pattern for Kubernetes API types.
+	SchemeBuilder.Register(
+		&GlobalNutanixIPPool{},
+		&GlobalNutanixIPPoolList{},
+	)
+}
+
+// PoolSpec implements the generic NutanixIPPool interface.
+func (p *GlobalNutanixIPPool) PoolSpec() *NutanixIPPoolSpec {
+	return &p.Spec
+}
+
+// PoolStatus implements the generic NutanixIPPool interface.
+func (p *GlobalNutanixIPPool) PoolStatus() *NutanixIPPoolStatus {
+	return &p.Status
+}
+
+// GetConditions returns the set of conditions for this object.
+func (p *GlobalNutanixIPPool) GetConditions() []metav1.Condition {
+	return p.Status.Conditions
+}
+
+// SetConditions sets conditions for this object.
+func (p *GlobalNutanixIPPool) SetConditions(conditions []metav1.Condition) {
+	p.Status.Conditions = conditions
+}
@@ -10,10 +10,36 @@ import (
 const (
 	// NutanixIPPoolKind is the kind for NutanixIPPool objects.
 	NutanixIPPoolKind = "NutanixIPPool"
+
+	// IPFamilyAnnotation is the annotation on an IPAddressClaim used to select the IP family of the address to
+	// allocate from a dual-stack pool. Valid values are IPv4 and IPv6. If not set, the address is allocated from
+	// the pool's Subnet.
+	IPFamilyAnnotation = "ipam.nutanix.com/ip-family"
+
+	// RequestedAddressAnnotation is the annotation on an IPAddressClaim used to request a specific IP address
+	// rather than any free IP of the pool's subnet. The IP family of the address selects the subnet of a
+	// dual-stack pool. Allocation fails if the address is already reserved in Prism Central.
+	RequestedAddressAnnotation = "ipam.nutanix.com/requested-address"
+
+	// SubnetAnnotation is the annotation on an IPAddress recording the extID of the Nutanix subnet the address
+	// was reserved in, so that it is released from the same subnet.
+	SubnetAnnotation = "ipam.nutanix.com/subnet"
+
+	// ClientContextAnnotation is the annotation on an IPAddressClaim and its IPAddress recording the client context
+	// the IP was reserved with in Prism Central. It is set to the client context rendered from the
+	// clientContextTemplate of the pool, or to the UID of the claim if the pool has none, when the IP is reserved,
+	// and is used when the IP is released, as clusterctl move recreates the claim with another UID.
+	ClientContextAnnotation = "ipam.nutanix.com/client-context"
+
+	// ProtectPoolFinalizer is the finalizer that prevents the deletion of a pool while IPAddresses allocated
+	// from it exist, as the pool is required to release their IPs.
+	ProtectPoolFinalizer = "ipam.cluster.x-k8s.io/ProtectPool"
 )
 
 // NutanixIPPoolSpec defines the desired state of NutanixIPPool.
 // +kubebuilder:validation:XValidation:message="cluster is required if subnet is not a valid uuid",rule="self.subnet.lowerAscii().matches('^[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}$') || (has(self.cluster) && self.cluster.size() > 0)"
+// +kubebuilder:validation:XValidation:message="cluster is required if ipv6Subnet is not a valid uuid",rule="!has(self.ipv6Subnet) || self.ipv6Subnet.lowerAscii().matches('^[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}$') || (has(self.cluster) && self.cluster.size() > 0)"
+// +kubebuilder:validation:XValidation:message="cluster is required if a fallback subnet is not a valid uuid",rule="!has(self.fallbackSubnets) || (has(self.cluster) && self.cluster.size() > 0) || self.fallbackSubnets.all(s, s.subnet.lowerAscii().matches('^[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}$') || (has(s.cluster) && s.cluster.size() > 0))"
 type NutanixIPPoolSpec struct {
 	// PrismCentral is the configuration details of the Prism Central instance to use for IPAM.
 	// +kubebuilder:validation:Required
@@ -23,13 +49,80 @@ type NutanixIPPoolSpec struct {
 	// This must be either a UUID or the name of a subnet.
 	// When a name is used, the Cluster field must be set to the UUID of the PE cluster to use
 	// in order to resolve the name to a UUID.
+	// When IPv6Subnet is set, this must be an IPv4 subnet.
+	// +kubebuilder:validation:Required
+	Subnet string `json:"subnet"`
+
+	// IPv6Subnet is the Nutanix subnet to allocate IPv6 IPs from for dual-stack pools.
+	// This must be either a UUID or the name of a subnet, resolved in the same way as Subnet.
+	// IPAddressClaims select the subnet to allocate from via the ipam.nutanix.com/ip-family annotation.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	IPv6Subnet *string `json:"ipv6Subnet,omitempty"`
+
+	// FallbackSubnets are additional Nutanix subnets to allocate IPs from, in order, once Subnet and the
+	// preceding fallback subnets have no free IPs left. They only apply to addresses allocated from Subnet, not to
+	// IPv6 addresses allocated from IPv6Subnet.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=atomic
+	FallbackSubnets []NutanixIPPoolFallbackSubnet `json:"fallbackSubnets,omitempty"`
+
+	// Cluster is the Nutanix PE cluster to use to resolve the Subnet and IPv6Subnet names to UUIDs.
+	// Cluster can either be the name or the UUID of the PE cluster.
+	// This field is only required when Subnet or IPv6Subnet is a name rather than a UUID.
+	// +kubebuilder:validation:Optional
+	Cluster *string `json:"cluster,omitempty"`
+
+	// Gateway is the default gateway to set on allocated IPAddresses, overriding the default gateway configured
+	// on the Nutanix subnet. The override only applies to addresses of the same IP family as the gateway.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=39
+	Gateway *string `json:"gateway,omitempty"`
+
+	// AllowedRanges restricts the IPs allocated from the pool to the given addresses within the IP pools of the
+	// subnets. Each address is either a CIDR (e.g. 10.0.0.0/24), an IP range (e.g. 10.0.0.10-10.0.0.20) or a
+	// single IP. If empty, any free IP of the subnets' IP pools can be allocated.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=87
+	// +listType=atomic
+	AllowedRanges []string `json:"allowedRanges,omitempty"`
+
+	// ExcludedAddresses are never allocated from the pool, e.g. because they are assigned to VMs outside of
+	// Kubernetes. Each address is either a CIDR, an IP range or a single IP, as for AllowedRanges.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:MaxLength=87
+	// +listType=atomic
+	ExcludedAddresses []string `json:"excludedAddresses,omitempty"`
+
+	// ClientContextTemplate is the Go template rendering the client context IPs are reserved with in Prism Central,
+	// so that reservations can be traced back to their IPAddressClaim in Prism Central. The template may only
+	// consist of text and of the fields {{ .ClusterName }}, {{ .Namespace }}, {{ .Name }} and {{ .UID }} of the
+	// claim, and must reference {{ .UID }}, e.g. "{{ .ClusterName }}/{{ .Namespace }}/{{ .Name }}/{{ .UID }}".
+	// The client context is recorded on the claim when its IP is reserved, so changing the template only applies
+	// to IPs reserved afterwards. If not set, the UID of the claim is used as client context.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	ClientContextTemplate *string `json:"clientContextTemplate,omitempty"`
+}
+
+// NutanixIPPoolFallbackSubnet is a Nutanix subnet to allocate IPs from once the preceding subnets of the pool are
+// exhausted.
+type NutanixIPPoolFallbackSubnet struct {
+	// Subnet is the Nutanix subnet to allocate IPs from.
+	// This must be either a UUID or the name of a subnet, resolved in the same way as the pool's Subnet.
 	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
 	Subnet string `json:"subnet"`
 
-	// Cluster is the Nutanix PE cluster to use to resolve the Subnet name to a UUID.
+	// Cluster is the Nutanix PE cluster to use to resolve the Subnet name to a UUID, overriding the pool's Cluster.
 	// Cluster can either be the name or the UUID of the PE cluster.
-	// This field is only required when Subnet is a name rather than a UUID.
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=256
 	Cluster *string `json:"cluster,omitempty"`
 }
 
@@ -79,24 +172,124 @@ type LocalConfigMapRef struct {
 	// Name is the name of the referenced configmap.
 	// +kubebuilder:validation:Required
 	Name string `json:"name"`
+
+	// Namespace is the namespace of the referenced configmap. This field is required for a GlobalNutanixIPPool
+	// and must not be set for a NutanixIPPool, which always references a configmap in its own namespace.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type LocalSecretRef struct {
 	// Name is the name of the referenced secret.
 	// +kubebuilder:validation:Required
 	Name string `json:"name"`
+
+	// Namespace is the namespace of the referenced secret. This field is required for a GlobalNutanixIPPool
+	// and must not be set for a NutanixIPPool, which always references a secret in its own namespace.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // NutanixIPPoolStatus defines the observed state of NutanixIPPool.
-type NutanixIPPoolStatus struct{}
+type NutanixIPPoolStatus struct {
+	// Conditions represents the observations of the pool's current state.
+	// Known condition types are Ready, PrismCentralReachable, CredentialsValid, ClusterResolved,
+	// SubnetResolved and Deleting.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=32
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Addresses reports the count of total, used and free IPs in the subnet, as well as the count of IPs
+	// reserved by this provider for claims referencing this pool. For dual-stack pools and pools with fallback
+	// subnets, the counts are the sum over all subnets.
+	// +kubebuilder:validation:Optional
+	Addresses *NutanixIPPoolStatusIPAddresses `json:"ipAddresses,omitempty"`
+
+	// Subnet is the subnet resolved from the spec.
+	// +kubebuilder:validation:Optional
+	Subnet *NutanixIPPoolStatusSubnet `json:"subnet,omitempty"`
+
+	// IPv6Subnet is the IPv6 subnet resolved from the spec, if set.
+	// +kubebuilder:validation:Optional
+	IPv6Subnet *NutanixIPPoolStatusSubnet `json:"ipv6Subnet,omitempty"`
+
+	// FallbackSubnets are the fallback subnets resolved from the spec, in the same order.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	FallbackSubnets []NutanixIPPoolStatusSubnet `json:"fallbackSubnets,omitempty"`
+
+	// ClusterExtID is the extID of the PE cluster the subnet belongs to. This is empty if the subnet is
+	// not attached to a cluster, e.g. an overlay subnet.
+	// +kubebuilder:validation:Optional
+	ClusterExtID string `json:"clusterExtID,omitempty"`
+}
+
+// NutanixIPPoolStatusIPAddresses contains the count of total, used and free IPs in the subnet, as well as
+// the count of IPs reserved by this provider.
+type NutanixIPPoolStatusIPAddresses struct {
+	// Total is the total number of IPs in the IP pool ranges configured on the subnet.
+	// +kubebuilder:validation:Required
+	Total int64 `json:"total"`
+
+	// Used is the number of IPs in the subnet that are in use, as reported by Prism Central.
+	// +kubebuilder:validation:Required
+	Used int64 `json:"used"`
+
+	// Free is the number of IPs in the subnet that are available for allocation, as reported by
+	// Prism Central.
+	// +kubebuilder:validation:Required
+	Free int64 `json:"free"`
+
+	// Reserved is the number of IPs reserved by this provider for claims referencing this pool.
+	// +kubebuilder:validation:Required
+	Reserved int64 `json:"reserved"`
+}
+
+// NutanixIPPoolStatusSubnet contains the details of the subnet resolved from the spec.
+type NutanixIPPoolStatusSubnet struct {
+	// ExtID is the extID of the subnet.
+	// +kubebuilder:validation:Required
+	ExtID string `json:"extID"`
+
+	// Prefix is the prefix length of the subnet.
+	// +kubebuilder:validation:Required
+	Prefix int32 `json:"prefix"`
+
+	// Gateway is the default gateway configured on the subnet.
+	// +kubebuilder:validation:Optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// DNSServers are the DNS servers configured on the subnet.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	DNSServers []string `json:"dnsServers,omitempty"`
+
+	// SearchDomains is the domain search list configured on the subnet.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	SearchDomains []string `json:"searchDomains,omitempty"`
+}
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories=cluster-api
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the pool can be used to allocate IPs"
 // +kubebuilder:printcolumn:name="Subnet",type="string",JSONPath=".spec.subnet",description="Subnet to allocate IPs from"
+// +kubebuilder:printcolumn:name="IPv6 Subnet",type="string",JSONPath=".spec.ipv6Subnet",description="Optional subnet to allocate IPv6 IPs from",priority=1
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster",description="Optional PE Cluster to allocate IPs from (only required if Subnet is a name rather than a uuid)"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.ipAddresses.total",description="Total number of IPs in the subnet's IP pools"
+// +kubebuilder:printcolumn:name="Used",type="integer",JSONPath=".status.ipAddresses.used",description="Number of IPs in use in the subnet"
+// +kubebuilder:printcolumn:name="Free",type="integer",JSONPath=".status.ipAddresses.free",description="Number of IPs available for allocation in the subnet"
+// +kubebuilder:printcolumn:name="Reserved",type="integer",JSONPath=".status.ipAddresses.reserved",description="Number of IPs reserved by this provider for claims referencing this pool"
+// +kubebuilder:printcolumn:name="Subnet ExtID",type="string",JSONPath=".status.subnet.extID",description="Resolved subnet extID",priority=1
+// +kubebuilder:printcolumn:name="Prefix",type="integer",JSONPath=".status.subnet.prefix",description="Resolved subnet prefix length",priority=1
+// +kubebuilder:printcolumn:name="Cluster ExtID",type="string",JSONPath=".status.clusterExtID",description="Resolved PE cluster extID",priority=1
 
 // NutanixIPPool is the Schema for the nutanixippools API.
+// +kubebuilder:validation:XValidation:message="credentialsSecretRef.namespace must not be set, the secret must be in the same namespace as the pool",rule="!has(self.spec.prismCentral.credentialsSecretRef.__namespace__)"
+// +kubebuilder:validation:XValidation:message="trustBundleConfigMapRef.namespace must not be set, the configmap must be in the same namespace as the pool",rule="!has(self.spec.prismCentral.additionalTrustBundle) || !has(self.spec.prismCentral.additionalTrustBundle.trustBundleConfigMapRef) || !has(self.spec.prismCentral.additionalTrustBundle.trustBundleConfigMapRef.__namespace__)"
 type NutanixIPPool struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -130,3 +323,22 @@ func (p *NutanixIPPool) PoolSpec() *NutanixIPPoolSpec {
 func (p *NutanixIPPool) PoolStatus() *NutanixIPPoolStatus {
 	return &p.Status
 }
+
+// ReferenceNamespace returns the namespace of a secret or configmap referenced by a pool. A NutanixIPPool
+// references objects in its own namespace, while a GlobalNutanixIPPool must specify the namespace explicitly.
+func ReferenceNamespace(pool metav1.Object, namespace string) string {
+	if namespace != "" {
+		return namespace
+	}
+	return pool.GetNamespace()
+}
+
+// GetConditions returns the set of conditions for this object.
+func (p *NutanixIPPool) GetConditions() []metav1.Condition {
+	return p.Status.Conditions
+}
+
+// SetConditions sets conditions for this object.
+func (p *NutanixIPPool) SetConditions(conditions []metav1.Condition) {
+	p.Status.Conditions = conditions
+}
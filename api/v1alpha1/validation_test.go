@@ -108,6 +108,123 @@ var _ = DescribeTableSubtree(
 		Subnet: "example-subnet-name",
 	}, true),
 
+	Entry("success with ipv6 subnet uuid", v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name: "test-secret",
+			},
+		},
+		Subnet:     uuid.NewString(),
+		IPv6Subnet: new(uuid.NewString()),
+	}, false),
+
+	Entry("success with cluster and named ipv6 subnet", v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name: "test-secret",
+			},
+		},
+		Subnet:     "example-subnet-name",
+		IPv6Subnet: new("example-ipv6-subnet-name"),
+		Cluster:    new("example-cluster-name"),
+	}, false),
+
+	Entry("success with ipv4 gateway", v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name: "test-secret",
+			},
+		},
+		Subnet:  uuid.NewString(),
+		Gateway: new("10.0.0.1"),
+	}, false),
+
+	Entry("success with ipv6 gateway", v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name: "test-secret",
+			},
+		},
+		Subnet:  uuid.NewString(),
+		Gateway: new("fd00::1"),
+	}, false),
+
+	Entry("failure with invalid gateway", v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name: "test-secret",
+			},
+		},
+		Subnet:  uuid.NewString(),
+		Gateway: new("gateway.example.com"),
+	}, true),
+
+	Entry("failure with missing cluster and named ipv6 subnet", v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name: "test-secret",
+			},
+		},
+		Subnet:     uuid.NewString(),
+		IPv6Subnet: new("example-ipv6-subnet-name"),
+	}, true),
+
+	Entry("success with fallback subnet uuids", v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name: "test-secret",
+			},
+		},
+		Subnet: uuid.NewString(),
+		FallbackSubnets: []v1alpha1.NutanixIPPoolFallbackSubnet{
+			{Subnet: uuid.NewString()},
+			{Subnet: uuid.NewString()},
+		},
+	}, false),
+
+	Entry("success with named fallback subnet and its own cluster", v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name: "test-secret",
+			},
+		},
+		Subnet: uuid.NewString(),
+		FallbackSubnets: []v1alpha1.NutanixIPPoolFallbackSubnet{{
+			Subnet:  "example-fallback-subnet-name",
+			Cluster: new("example-cluster-name"),
+		}},
+	}, false),
+
+	Entry("failure with missing cluster and named fallback subnet", v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name: "test-secret",
+			},
+		},
+		Subnet: uuid.NewString(),
+		FallbackSubnets: []v1alpha1.NutanixIPPoolFallbackSubnet{{
+			Subnet: "example-fallback-subnet-name",
+		}},
+	}, true),
+
 	Entry("failure with both additionalTrustBundle data and ref set", v1alpha1.NutanixIPPoolSpec{
 		PrismCentral: v1alpha1.PrismCentral{
 			Address: "127.0.0.1",
@@ -175,3 +292,66 @@ var _ = DescribeTableSubtree(
 		true,
 	),
 )
+
+var _ = DescribeTable(
+	"reference namespace validation",
+	func(obj client.Object, wantErr bool) {
+		err := k8sClient.Create(context.Background(), obj)
+		if wantErr {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Delete(context.Background(), obj)).To(Succeed())
+	},
+
+	Entry("success with namespaced pool and no reference namespaces", &v1alpha1.NutanixIPPool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, GenerateName: "test-"},
+		Spec:       newSpecWithReferenceNamespaces("", ""),
+	}, false),
+
+	Entry("failure with namespaced pool and credentials secret namespace", &v1alpha1.NutanixIPPool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, GenerateName: "test-"},
+		Spec:       newSpecWithReferenceNamespaces("other", ""),
+	}, true),
+
+	Entry("failure with namespaced pool and trust bundle configmap namespace", &v1alpha1.NutanixIPPool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, GenerateName: "test-"},
+		Spec:       newSpecWithReferenceNamespaces("", "other"),
+	}, true),
+
+	Entry("success with global pool and reference namespaces", &v1alpha1.GlobalNutanixIPPool{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "test-"},
+		Spec:       newSpecWithReferenceNamespaces("other", "other"),
+	}, false),
+
+	Entry("failure with global pool and no credentials secret namespace", &v1alpha1.GlobalNutanixIPPool{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "test-"},
+		Spec:       newSpecWithReferenceNamespaces("", "other"),
+	}, true),
+
+	Entry("failure with global pool and no trust bundle configmap namespace", &v1alpha1.GlobalNutanixIPPool{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "test-"},
+		Spec:       newSpecWithReferenceNamespaces("other", ""),
+	}, true),
+)
+
+func newSpecWithReferenceNamespaces(secretNamespace, configMapNamespace string) v1alpha1.NutanixIPPoolSpec {
+	return v1alpha1.NutanixIPPoolSpec{
+		PrismCentral: v1alpha1.PrismCentral{
+			Address: "127.0.0.1",
+			Port:    9440,
+			CredentialsSecretRef: v1alpha1.LocalSecretRef{
+				Name:      "test-secret",
+				Namespace: secretNamespace,
+			},
+			AdditionalTrustBundle: &v1alpha1.AdditionalTrustBundle{
+				ConfigMapReference: &v1alpha1.LocalConfigMapRef{
+					Name:      "example-config-map-name",
+					Namespace: configMapNamespace,
+				},
+			},
+		},
+		Subnet: uuid.NewString(),
+	}
+}
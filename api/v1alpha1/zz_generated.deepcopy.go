@@ -8,6 +8,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -36,6 +37,65 @@ func (in *AdditionalTrustBundle) DeepCopy() *AdditionalTrustBundle {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalNutanixIPPool) DeepCopyInto(out *GlobalNutanixIPPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalNutanixIPPool.
+func (in *GlobalNutanixIPPool) DeepCopy() *GlobalNutanixIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalNutanixIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlobalNutanixIPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalNutanixIPPoolList) DeepCopyInto(out *GlobalNutanixIPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GlobalNutanixIPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalNutanixIPPoolList.
+func (in *GlobalNutanixIPPoolList) DeepCopy() *GlobalNutanixIPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalNutanixIPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlobalNutanixIPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalConfigMapRef) DeepCopyInto(out *LocalConfigMapRef) {
 	*out = *in
@@ -72,7 +132,7 @@ func (in *NutanixIPPool) DeepCopyInto(out *NutanixIPPool) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NutanixIPPool.
@@ -93,6 +153,26 @@ func (in *NutanixIPPool) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NutanixIPPoolFallbackSubnet) DeepCopyInto(out *NutanixIPPoolFallbackSubnet) {
+	*out = *in
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NutanixIPPoolFallbackSubnet.
+func (in *NutanixIPPoolFallbackSubnet) DeepCopy() *NutanixIPPoolFallbackSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(NutanixIPPoolFallbackSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NutanixIPPoolList) DeepCopyInto(out *NutanixIPPoolList) {
 	*out = *in
@@ -129,11 +209,43 @@ func (in *NutanixIPPoolList) DeepCopyObject() runtime.Object {
 func (in *NutanixIPPoolSpec) DeepCopyInto(out *NutanixIPPoolSpec) {
 	*out = *in
 	in.PrismCentral.DeepCopyInto(&out.PrismCentral)
+	if in.IPv6Subnet != nil {
+		in, out := &in.IPv6Subnet, &out.IPv6Subnet
+		*out = new(string)
+		**out = **in
+	}
+	if in.FallbackSubnets != nil {
+		in, out := &in.FallbackSubnets, &out.FallbackSubnets
+		*out = make([]NutanixIPPoolFallbackSubnet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Cluster != nil {
 		in, out := &in.Cluster, &out.Cluster
 		*out = new(string)
 		**out = **in
 	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(string)
+		**out = **in
+	}
+	if in.AllowedRanges != nil {
+		in, out := &in.AllowedRanges, &out.AllowedRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedAddresses != nil {
+		in, out := &in.ExcludedAddresses, &out.ExcludedAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClientContextTemplate != nil {
+		in, out := &in.ClientContextTemplate, &out.ClientContextTemplate
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NutanixIPPoolSpec.
@@ -149,6 +261,35 @@ func (in *NutanixIPPoolSpec) DeepCopy() *NutanixIPPoolSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NutanixIPPoolStatus) DeepCopyInto(out *NutanixIPPoolStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = new(NutanixIPPoolStatusIPAddresses)
+		**out = **in
+	}
+	if in.Subnet != nil {
+		in, out := &in.Subnet, &out.Subnet
+		*out = new(NutanixIPPoolStatusSubnet)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPv6Subnet != nil {
+		in, out := &in.IPv6Subnet, &out.IPv6Subnet
+		*out = new(NutanixIPPoolStatusSubnet)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FallbackSubnets != nil {
+		in, out := &in.FallbackSubnets, &out.FallbackSubnets
+		*out = make([]NutanixIPPoolStatusSubnet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NutanixIPPoolStatus.
@@ -161,6 +302,46 @@ func (in *NutanixIPPoolStatus) DeepCopy() *NutanixIPPoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NutanixIPPoolStatusIPAddresses) DeepCopyInto(out *NutanixIPPoolStatusIPAddresses) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NutanixIPPoolStatusIPAddresses.
+func (in *NutanixIPPoolStatusIPAddresses) DeepCopy() *NutanixIPPoolStatusIPAddresses {
+	if in == nil {
+		return nil
+	}
+	out := new(NutanixIPPoolStatusIPAddresses)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NutanixIPPoolStatusSubnet) DeepCopyInto(out *NutanixIPPoolStatusSubnet) {
+	*out = *in
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchDomains != nil {
+		in, out := &in.SearchDomains, &out.SearchDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NutanixIPPoolStatusSubnet.
+func (in *NutanixIPPoolStatusSubnet) DeepCopy() *NutanixIPPoolStatusSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(NutanixIPPoolStatusSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrismCentral) DeepCopyInto(out *PrismCentral) {
 	*out = *in
@@ -3,6 +3,7 @@
 
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 package main
 
 import (
@@ -33,10 +34,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
+	pcclient "github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/client"
 	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/controllers"
 	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/index"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/webhooks"
 )
 
 func main() {
@@ -94,6 +98,23 @@ func main() {
 		"The namespace of the resource that leader election will use for holding the leader lock.",
 	)
 
+	webhookOptions := webhook.Options{
+		Port: 9444,
+	}
+	pflag.CommandLine.IntVar(
+		&webhookOptions.Port,
+		"admission-webhook-port",
+		webhookOptions.Port,
+		"The port that the admission webhook server serves at.",
+	)
+	pflag.CommandLine.StringVar(
+		&webhookOptions.CertDir,
+		"admission-webhook-cert-dir",
+		"",
+		"The directory that contains the admission webhook server key and certificate. "+
+			"If unspecified, the default controller-runtime directory is used.",
+	)
+
 	logOptions := logs.NewOptions()
 
 	// Initialize and parse command line flags.
@@ -113,6 +134,14 @@ func main() {
 	reconcilerOpts := controllers.DefaultReconcilerOptions()
 	reconcilerOpts.AddFlags(pflag.CommandLine)
 
+	resolutionCacheTTL := pcclient.DefaultResolutionCacheTTL
+	pflag.CommandLine.DurationVar(
+		&resolutionCacheTTL,
+		"prism-resolution-cache-ttl",
+		resolutionCacheTTL,
+		"Time subnets and clusters resolved from Prism Central are cached for. Set to 0 to disable the cache.",
+	)
+
 	logs.AddFlags(pflag.CommandLine, logs.SkipLoggingConfigurationFlags())
 	logsv1.AddFlags(logOptions, pflag.CommandLine)
 	pflag.CommandLine.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
@@ -135,6 +164,12 @@ func main() {
 		}
 	}
 
+	mgrOptions.WebhookServer = webhook.NewServer(webhookOptions)
+
+	pcclient.SetResolutionCacheTTL(resolutionCacheTTL)
+	pcclient.SetLimits(reconcilerOpts.PrismCentralLimits())
+	pcclient.SetCircuitBreakerSettings(reconcilerOpts.PrismCentralCircuitBreaker())
+
 	// Validates logs flags using Kubernetes component-base machinery and applies them
 	if err := logsv1.ValidateAndApply(logOptions, nil); err != nil {
 		setupLog.Error(err, "unable to apply logging configuration")
@@ -172,12 +207,48 @@ func main() {
 			watchFilter,
 			secretInformer,
 			configMapInformer,
+			mgr.GetEventRecorder("caipamx-ipaddressclaim"),
 			reconcilerOpts,
 		),
 	}).SetupWithManager(signalCtx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "IPAddressClaim")
 		os.Exit(1)
 	}
+	if err = controllers.NewNutanixIPPoolReconciler(
+		mgr.GetClient(),
+		watchFilter,
+		secretInformer,
+		configMapInformer,
+		reconcilerOpts,
+	).SetupWithManager(signalCtx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NutanixIPPool")
+		os.Exit(1)
+	}
+	if err = controllers.NewGlobalNutanixIPPoolReconciler(
+		mgr.GetClient(),
+		watchFilter,
+		secretInformer,
+		configMapInformer,
+		reconcilerOpts,
+	).SetupWithManager(signalCtx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GlobalNutanixIPPool")
+		os.Exit(1)
+	}
+	if err = controllers.NewReservationGCReconciler(
+		mgr.GetClient(),
+		watchFilter,
+		secretInformer,
+		configMapInformer,
+		mgr.GetEventRecorder("caipamx-reservation-gc"),
+		reconcilerOpts,
+	).SetupWithManager(signalCtx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ReservationGC")
+		os.Exit(1)
+	}
+	if err = webhooks.SetupWebhooksWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhooks")
+		os.Exit(1)
+	}
 	if err := mgr.Start(signalCtx); err != nil {
 		setupLog.Error(err, "unable to start controller manager")
 		os.Exit(1)
@@ -0,0 +1,227 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	networkingclient "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/client"
+)
+
+// CircuitBreakerSettings configure the circuit breaker of each Prism Central, which stops sending requests to a
+// Prism Central after consecutive failures.
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive transport errors or 5xx responses after which the circuit
+	// breaker opens. Zero disables the circuit breaker.
+	FailureThreshold int
+
+	// CoolOff is the time the circuit breaker stays open for before a probe request is let through.
+	CoolOff time.Duration
+}
+
+// DefaultCircuitBreakerSettings are the default settings of the circuit breaker of each Prism Central.
+var DefaultCircuitBreakerSettings = CircuitBreakerSettings{
+	FailureThreshold: 5,
+	CoolOff:          30 * time.Second,
+}
+
+var breakers = newEndpointBreakers(DefaultCircuitBreakerSettings)
+
+// SetCircuitBreakerSettings sets the settings of the circuit breaker of each Prism Central.
+func SetCircuitBreakerSettings(settings CircuitBreakerSettings) {
+	breakers.setSettings(settings)
+}
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitHalfOpen lets a single probe request through once the cool-off has passed.
+	CircuitHalfOpen
+	// CircuitOpen rejects all requests until the cool-off has passed.
+	CircuitOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitHalfOpen:
+		return "half-open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// endpointBreakers holds the circuit breaker of each Prism Central endpoint.
+type endpointBreakers struct {
+	mu         sync.Mutex
+	settings   CircuitBreakerSettings
+	byEndpoint map[string]*circuitBreaker
+}
+
+func newEndpointBreakers(settings CircuitBreakerSettings) *endpointBreakers {
+	return &endpointBreakers{
+		settings:   settings,
+		byEndpoint: map[string]*circuitBreaker{},
+	}
+}
+
+func (b *endpointBreakers) setSettings(settings CircuitBreakerSettings) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.settings = settings
+	clear(b.byEndpoint)
+}
+
+// get returns the circuit breaker of the Prism Central at the endpoint, in host:port form.
+func (b *endpointBreakers) get(endpoint string) *circuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	breaker, ok := b.byEndpoint[endpoint]
+	if !ok {
+		breaker = newCircuitBreaker(endpoint, b.settings)
+		b.byEndpoint[endpoint] = breaker
+	}
+	return breaker
+}
+
+// circuitBreaker stops sending requests to a Prism Central once consecutive requests failed because it is
+// unreachable or unhealthy, so that reconciles fail fast instead of waiting for the requests to time out. After the
+// cool-off, a single probe request is let through, closing the circuit breaker again if it succeeds.
+type circuitBreaker struct {
+	endpoint string
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// probing is whether the probe request of the half-open circuit breaker is in flight.
+	probing bool
+}
+
+func newCircuitBreaker(endpoint string, settings CircuitBreakerSettings) *circuitBreaker {
+	breaker := &circuitBreaker{
+		endpoint: endpoint,
+		settings: settings,
+		now:      time.Now,
+	}
+	observeCircuitState(endpoint, CircuitClosed)
+	return breaker
+}
+
+// allow admits a request to the Prism Central. An error of kind ErrCircuitOpen is returned if the circuit breaker is
+// open, or if it is half-open and the probe request is already in flight.
+func (b *circuitBreaker) allow() error {
+	if b.settings.FailureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		retryIn := b.openedAt.Add(b.settings.CoolOff).Sub(b.now())
+		if retryIn > 0 {
+			return newError(
+				ErrCircuitOpen,
+				"Prism Central %s is unavailable after %d consecutive failures, retrying in %s",
+				b.endpoint,
+				b.failures,
+				retryIn.Round(time.Second),
+			)
+		}
+		b.setState(CircuitHalfOpen)
+		b.probing = true
+		return nil
+	case CircuitHalfOpen:
+		if b.probing {
+			return newError(
+				ErrCircuitOpen,
+				"Prism Central %s is unavailable after %d consecutive failures, waiting for a probe request",
+				b.endpoint,
+				b.failures,
+			)
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record records the outcome of an admitted request, opening the circuit breaker if the request failed because the
+// Prism Central is unreachable or unhealthy, and closing it otherwise.
+func (b *circuitBreaker) record(err error) {
+	if b.settings.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !isEndpointFailure(err) {
+		b.failures = 0
+		b.setState(CircuitClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.settings.FailureThreshold {
+		b.openedAt = b.now()
+		b.setState(CircuitOpen)
+	}
+}
+
+func (b *circuitBreaker) setState(state CircuitState) {
+	if b.state != state {
+		b.state = state
+		observeCircuitState(b.endpoint, state)
+	}
+}
+
+// isEndpointFailure returns whether a request failed because the Prism Central is unreachable or unhealthy, i.e.
+// with a transport error or a 5xx response.
+func isEndpointFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr networkingclient.GenericOpenAPIError
+	if errors.As(err, &apiErr) {
+		return statusCode(apiErr.Status) >= 500
+	}
+	var (
+		urlErr *url.Error
+		netErr net.Error
+	)
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || isTransientNetworkError(err)
+}
+
+// admit admits a request to the Prism Central of the client, subject to the limits and the circuit breaker of the
+// Prism Central. The returned function must be called with the error of the request once it completes.
+func (c *client) admit() (func(error), error) {
+	release, err := c.limiter.acquire()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.breaker.allow(); err != nil {
+		release()
+		return nil, err
+	}
+	return func(err error) {
+		c.breaker.record(err)
+		release()
+	}, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"syscall"
+	"time"
+
+	networkingclient "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("circuitBreaker", func() {
+	const endpoint = "breaker.example.com:9440"
+
+	var (
+		breaker *circuitBreaker
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		breaker = newCircuitBreaker(endpoint, CircuitBreakerSettings{FailureThreshold: 3, CoolOff: time.Minute})
+		breaker.now = func() time.Time {
+			return now
+		}
+	})
+
+	unavailable := networkingclient.GenericOpenAPIError{Status: "503 Service Unavailable"}
+
+	request := func(err error) error {
+		if allowErr := breaker.allow(); allowErr != nil {
+			return allowErr
+		}
+		breaker.record(err)
+		return err
+	}
+
+	circuitState := func() float64 {
+		return testutil.ToFloat64(prismCircuitBreakerState.WithLabelValues(endpoint))
+	}
+
+	It("should open after consecutive failures and reject requests during the cool-off", func() {
+		for range 2 {
+			Expect(request(unavailable)).To(MatchError(unavailable))
+		}
+		// A successful request resets the consecutive failures.
+		Expect(request(nil)).To(Succeed())
+		for range 3 {
+			Expect(request(unavailable)).To(MatchError(unavailable))
+		}
+		Expect(breaker.state).To(Equal(CircuitOpen))
+		Expect(circuitState()).To(BeNumerically("==", CircuitOpen))
+
+		err := request(nil)
+		Expect(err).To(MatchError(ErrCircuitOpen))
+		Expect(err).To(MatchError(ContainSubstring("retrying in 1m0s")))
+	})
+
+	It("should let a single probe request through after the cool-off and close if it succeeds", func() {
+		for range 3 {
+			Expect(request(unavailable)).To(MatchError(unavailable))
+		}
+		now = now.Add(time.Minute)
+
+		Expect(breaker.allow()).To(Succeed())
+		Expect(breaker.state).To(Equal(CircuitHalfOpen))
+		Expect(circuitState()).To(BeNumerically("==", CircuitHalfOpen))
+		Expect(breaker.allow()).To(MatchError(ErrCircuitOpen))
+
+		breaker.record(nil)
+		Expect(breaker.state).To(Equal(CircuitClosed))
+		Expect(circuitState()).To(BeNumerically("==", CircuitClosed))
+		Expect(request(nil)).To(Succeed())
+	})
+
+	It("should open again if the probe request fails", func() {
+		for range 3 {
+			Expect(request(unavailable)).To(MatchError(unavailable))
+		}
+		now = now.Add(time.Minute)
+
+		Expect(request(&url.Error{Op: "Get", URL: "https://" + endpoint, Err: syscall.ECONNREFUSED})).
+			To(MatchError(syscall.ECONNREFUSED))
+		Expect(breaker.state).To(Equal(CircuitOpen))
+		Expect(request(nil)).To(MatchError(ErrCircuitOpen))
+	})
+
+	It("should not open if the circuit breaker is disabled", func() {
+		breaker.settings.FailureThreshold = 0
+		for range 10 {
+			Expect(request(unavailable)).To(MatchError(unavailable))
+		}
+		Expect(breaker.state).To(Equal(CircuitClosed))
+	})
+})
+
+var _ = DescribeTable("isEndpointFailure",
+	func(err error, expected bool) {
+		Expect(isEndpointFailure(fmt.Errorf("request failed: %w", err))).To(Equal(expected))
+	},
+	Entry("internal server error", networkingclient.GenericOpenAPIError{Status: "500 Internal Server Error"}, true),
+	Entry("bad gateway", networkingclient.GenericOpenAPIError{Status: "502 Bad Gateway"}, true),
+	Entry("not found", networkingclient.GenericOpenAPIError{Status: "404 Not Found"}, false),
+	Entry("unauthorized", networkingclient.GenericOpenAPIError{Status: "401 Unauthorized"}, false),
+	Entry("too many requests", networkingclient.GenericOpenAPIError{Status: "429 Too Many Requests"}, false),
+	Entry("connection refused", &url.Error{Op: "Get", URL: "https://pc", Err: syscall.ECONNREFUSED}, true),
+	Entry("timeout", context.DeadlineExceeded, true),
+	Entry("canceled", &url.Error{Op: "Get", URL: "https://pc", Err: context.Canceled}, false),
+	Entry("other error", errors.New("no IP address reserved"), false),
+)
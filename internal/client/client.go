@@ -5,6 +5,7 @@ package client
 
 import (
 	"fmt"
+	"sync"
 
 	convergedv4 "github.com/nutanix-cloud-native/prism-go-client/converged/v4"
 	"github.com/nutanix-cloud-native/prism-go-client/environment/types"
@@ -15,12 +16,38 @@ var v4ClientCache = convergedv4.NewClientCache(v4sdk.WithSessionAuth(true))
 
 type CachedClientParams = types.CachedClientParams
 
+// VersionedClientParams are CachedClientParams with a version of the credentials and trust bundle the client is
+// configured with. A cached client is recreated when the version of its params changes, e.g. because the credentials
+// were rotated.
+type VersionedClientParams interface {
+	CachedClientParams
+	Version() string
+}
+
+var clients = newClientVersions(v4ClientCache.Delete)
+
+// EvictClient drops the cached client with the given key, if any, so that it is recreated with the current
+// credentials and trust bundle the next time it is requested.
+func EvictClient(key string) {
+	clients.evict(key)
+}
+
 func GetClient(params CachedClientParams) (Client, error) {
+	if versioned, ok := params.(VersionedClientParams); ok {
+		clients.update(versioned)
+	}
 	v4Client, err := v4ClientCache.GetOrCreate(params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create converged v4 API client: %w", err)
 	}
-	return &client{v4Client: v4Client}, nil
+	endpoint := endpointKey(params.ManagementEndpoint())
+	return &client{
+		v4Client:    v4Client,
+		endpoint:    endpoint,
+		resolutions: resolutions,
+		limiter:     limiters.get(endpoint),
+		breaker:     breakers.get(endpoint),
+	}, nil
 }
 
 type Client interface {
@@ -30,4 +57,55 @@ type Client interface {
 
 type client struct {
 	v4Client *convergedv4.Client
+
+	// endpoint is the host:port of the Prism Central, keying the resolutions cached for it.
+	endpoint    string
+	resolutions *resolutionCache
+	// limiter limits the requests sent to the Prism Central.
+	limiter *endpointLimiter
+	// breaker stops sending requests to the Prism Central while it is unavailable.
+	breaker *circuitBreaker
+}
+
+// endpointKey returns the host:port of the management endpoint.
+func endpointKey(me types.ManagementEndpoint) string {
+	if me.Address == nil {
+		return ""
+	}
+	return me.Address.Host
+}
+
+// clientVersions tracks the version of the params each cached client was created with, evicting clients whose
+// params changed.
+type clientVersions struct {
+	mu     sync.Mutex
+	params map[string]VersionedClientParams
+	delete func(CachedClientParams)
+}
+
+func newClientVersions(deleteFn func(CachedClientParams)) *clientVersions {
+	return &clientVersions{
+		params: map[string]VersionedClientParams{},
+		delete: deleteFn,
+	}
+}
+
+// update records the version of the params, evicting the cached client if it was created with a different version.
+func (v *clientVersions) update(params VersionedClientParams) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if current, ok := v.params[params.Key()]; ok && current.Version() != params.Version() {
+		v.delete(current)
+	}
+	v.params[params.Key()] = params
+}
+
+// evict drops the cached client with the key, if any.
+func (v *clientVersions) evict(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if current, ok := v.params[key]; ok {
+		v.delete(current)
+		delete(v.params, key)
+	}
 }
@@ -0,0 +1,72 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/nutanix-cloud-native/prism-go-client/environment/types"
+)
+
+type testClientParams struct {
+	key     string
+	version string
+}
+
+func (p testClientParams) ManagementEndpoint() types.ManagementEndpoint {
+	return types.ManagementEndpoint{}
+}
+
+func (p testClientParams) Key() string {
+	return p.key
+}
+
+func (p testClientParams) Version() string {
+	return p.version
+}
+
+var _ = Describe("clientVersions", func() {
+	var (
+		versions *clientVersions
+		deleted  []CachedClientParams
+	)
+
+	BeforeEach(func() {
+		deleted = nil
+		versions = newClientVersions(func(params CachedClientParams) {
+			deleted = append(deleted, params)
+		})
+	})
+
+	It("should keep the cached client while the version is unchanged", func() {
+		versions.update(testClientParams{key: "ns/pool", version: "1"})
+		versions.update(testClientParams{key: "ns/pool", version: "1"})
+		Expect(deleted).To(BeEmpty())
+	})
+
+	It("should evict the cached client when the version changes", func() {
+		versions.update(testClientParams{key: "ns/pool", version: "1"})
+		versions.update(testClientParams{key: "ns/other-pool", version: "1"})
+		versions.update(testClientParams{key: "ns/pool", version: "2"})
+		Expect(deleted).To(ConsistOf(testClientParams{key: "ns/pool", version: "1"}))
+
+		versions.update(testClientParams{key: "ns/pool", version: "2"})
+		Expect(deleted).To(HaveLen(1))
+	})
+
+	It("should evict the cached client by key", func() {
+		versions.update(testClientParams{key: "ns/pool", version: "1"})
+
+		versions.evict("ns/pool")
+		Expect(deleted).To(ConsistOf(testClientParams{key: "ns/pool", version: "1"}))
+
+		versions.evict("ns/pool")
+		versions.evict("ns/unknown-pool")
+		Expect(deleted).To(HaveLen(1))
+
+		versions.update(testClientParams{key: "ns/pool", version: "1"})
+		Expect(deleted).To(HaveLen(1))
+	})
+})
@@ -5,11 +5,12 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
-	"github.com/nutanix-cloud-native/prism-go-client/converged"
 	convergedv4 "github.com/nutanix-cloud-native/prism-go-client/converged/v4"
 )
 
@@ -34,10 +35,29 @@ func (c *client) Cluster() ClusterClient {
 
 type clusterClient struct {
 	v4Client *convergedv4.Client
-	client   Client
+	client   *client
 }
 
 func (n *clusterClient) GetCluster(ctx context.Context, cluster string) (*Cluster, error) {
+	key := resolutionKey{endpoint: n.client.endpoint, kind: clusterResolution, name: cluster}
+	if apiCluster, ok := getCached[*Cluster](n.client.resolutions, key); ok {
+		return apiCluster, nil
+	}
+
+	apiCluster, err := n.getCluster(ctx, cluster)
+	if err != nil {
+		if errors.Is(err, ErrClusterNotFound) {
+			n.client.resolutions.delete(key)
+		}
+		return nil, err
+	}
+	n.client.resolutions.set(key, apiCluster)
+	return apiCluster, nil
+}
+
+func (n *clusterClient) getCluster(ctx context.Context, cluster string) (_ *Cluster, reterr error) {
+	defer observeRequest(ctx, OperationGetCluster, "", time.Now(), &reterr)
+
 	clusterUUID, err := uuid.Parse(cluster)
 	if err == nil {
 		return n.getClusterByExtID(ctx, clusterUUID)
@@ -50,26 +70,32 @@ func (n *clusterClient) getClusterByName(
 	ctx context.Context,
 	clusterName string,
 ) (*Cluster, error) {
+	done, err := n.client.admit()
+	if err != nil {
+		return nil, err
+	}
 	apiClusters, err := n.v4Client.Clusters.List(
 		ctx,
-		converged.WithFilter(fmt.Sprintf(`name eq '%s'`, clusterName)),
+		odataFilter{}.eq("name", clusterName).option(),
 	)
+	done(err)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to find cluster uuid for cluster %s: %w",
 			clusterName,
-			err,
+			classifyError(err, nil),
 		)
 	}
 	if apiClusters == nil {
-		return nil, fmt.Errorf("no cluster found with name %q", clusterName)
+		return nil, newError(ErrClusterNotFound, "no cluster found with name %q", clusterName)
 	}
 
 	if len(apiClusters) == 0 {
-		return nil, fmt.Errorf("no cluster found with name %q", clusterName)
+		return nil, newError(ErrClusterNotFound, "no cluster found with name %q", clusterName)
 	}
 	if len(apiClusters) > 1 {
-		return nil, fmt.Errorf(
+		return nil, newError(
+			ErrAmbiguousName,
 			"multiple clusters (%d) found with name %q",
 			len(apiClusters),
 			clusterName,
@@ -99,16 +125,21 @@ func (n *clusterClient) getClusterByExtID(
 	ctx context.Context,
 	clusterExtID uuid.UUID,
 ) (*Cluster, error) {
+	done, err := n.client.admit()
+	if err != nil {
+		return nil, err
+	}
 	apiCluster, err := n.v4Client.Clusters.Get(ctx, clusterExtID.String())
+	done(err)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to find cluster with extID %q: %w",
 			clusterExtID,
-			err,
+			classifyError(err, ErrClusterNotFound),
 		)
 	}
 	if apiCluster == nil {
-		return nil, fmt.Errorf("no cluster found with extID %q", clusterExtID)
+		return nil, newError(ErrClusterNotFound, "no cluster found with extID %q", clusterExtID)
 	}
 
 	if apiCluster.ExtId == nil {
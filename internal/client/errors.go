@@ -0,0 +1,230 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+
+	networkingclient "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/client"
+	networkingerror "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/networking/v4/error"
+	"k8s.io/utils/ptr"
+)
+
+// Sentinel errors classifying the errors returned by the clients of this package. Use errors.Is to match them and
+// errors.As with *Error to access the details of the Prism Central API response, if any.
+var (
+	// ErrSubnetNotFound is returned if no subnet exists with the given extID or name.
+	ErrSubnetNotFound = errors.New("subnet not found")
+
+	// ErrClusterNotFound is returned if no cluster exists with the given extID or name.
+	ErrClusterNotFound = errors.New("cluster not found")
+
+	// ErrAmbiguousName is returned if a subnet or cluster name matches more than one object.
+	ErrAmbiguousName = errors.New("name matches multiple objects")
+
+	// ErrPoolExhausted is returned if the IP pools of a subnet have no free IPs left to reserve.
+	ErrPoolExhausted = errors.New("IP pool exhausted")
+
+	// ErrAddressAlreadyReserved is returned if an IP to reserve is already reserved or in use.
+	ErrAddressAlreadyReserved = errors.New("IP address already reserved")
+
+	// ErrUnauthorized is returned if Prism Central rejects the credentials or they lack the required permissions.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrTransient is returned for errors that are expected to resolve on their own, e.g. if Prism Central is
+	// overloaded, rate limits requests or the connection timed out.
+	ErrTransient = errors.New("transient error")
+
+	// ErrThrottled is returned if a request was not sent because it exceeds the rate or concurrency limits of the
+	// Prism Central, see Limits.
+	ErrThrottled = errors.New("request throttled")
+
+	// ErrCircuitOpen is returned if a request was not sent because the circuit breaker of the Prism Central is open
+	// after consecutive failures, see CircuitBreakerSettings.
+	ErrCircuitOpen = errors.New("circuit breaker open")
+
+	// errNotReserved is returned if there are no IPs reserved for a client context to unreserve.
+	errNotReserved = errors.New("IP address not reserved")
+)
+
+// Error is an error classified as one of the sentinel errors of this package.
+type Error struct {
+	// Kind is the sentinel error the error is classified as, e.g. ErrSubnetNotFound.
+	Kind error
+
+	// StatusCode is the HTTP status code of the Prism Central API response, or 0 if the error did not originate
+	// from an API response.
+	StatusCode int
+
+	// Code is the code of the first message of the Prism Central API error response, e.g. NET-40000, if any.
+	Code string
+
+	message string
+	err     error
+}
+
+func (e *Error) Error() string {
+	if e.message == "" {
+		return e.Kind.Error()
+	}
+	return e.message
+}
+
+// Is reports whether the error is classified as the target sentinel error.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// newError returns a new error of the given kind with a formatted message.
+func newError(kind error, format string, args ...any) error {
+	return &Error{Kind: kind, message: fmt.Sprintf(format, args...)}
+}
+
+// classifyError classifies an error returned by the v4 API as one of the sentinel errors, keeping its message.
+// notFound is the error kind of a 404 response, as it depends on the requested object. The error is returned
+// unchanged if it cannot be classified.
+//
+// API error responses are classified by the code of their first message, e.g. NET-40900, whose first three digits
+// are the HTTP status of the error, or by the HTTP status of the response if the message has no code. Errors of
+// failed tasks carry neither a code nor an HTTP status, so they are classified by their message as a fallback.
+func classifyError(err, notFound error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	classified = unclassifiedError(err)
+	status := codeStatus(classified.Code)
+	if status == 0 {
+		status = classified.StatusCode
+	}
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		classified.Kind = ErrUnauthorized
+	case status == http.StatusNotFound && notFound != nil:
+		classified.Kind = notFound
+	case status == http.StatusConflict:
+		classified.Kind = ErrAddressAlreadyReserved
+	case status == http.StatusTooManyRequests,
+		status == http.StatusBadGateway,
+		status == http.StatusServiceUnavailable,
+		status == http.StatusGatewayTimeout,
+		isTransientNetworkError(err):
+		classified.Kind = ErrTransient
+	default:
+		classified.Kind = classifyMessage(err.Error())
+		if classified.Kind == nil {
+			return err
+		}
+	}
+	return classified
+}
+
+// unclassifiedError returns an Error wrapping err with the details of its API response, if any, but no kind.
+func unclassifiedError(err error) *Error {
+	unclassified := &Error{message: err.Error(), err: err}
+	var apiErr networkingclient.GenericOpenAPIError
+	if errors.As(err, &apiErr) {
+		unclassified.StatusCode = statusCode(apiErr.Status)
+		if messages := apiErrorMessages(apiErr.Body); len(messages) > 0 {
+			unclassified.Code = ptr.Deref(messages[0].Code, "")
+		}
+	}
+	return unclassified
+}
+
+// poolExhaustedError classifies an error of a reservation of count IPs as ErrPoolExhausted if the refreshed
+// subnet has fewer free IPs than requested. Exhausted IP pools are identified by the IP usage of the subnet as
+// the API has no error code for them and the messages of its errors are not part of the API contract. The error
+// is returned unchanged if it is already classified or the IP usage of the subnet is unknown.
+func poolExhaustedError(err error, subnet *Subnet, count int64) error {
+	if errors.As(err, new(*Error)) || subnet == nil || subnet.IPUsage() == nil || subnet.IPUsage().Free >= count {
+		return err
+	}
+	exhausted := unclassifiedError(err)
+	exhausted.Kind = ErrPoolExhausted
+	return exhausted
+}
+
+// codeStatus returns the HTTP status encoded in the first three digits of a v4 API message code, e.g. 409 for
+// NET-40900, or 0 if the code is not of this form.
+func codeStatus(code string) int {
+	_, digits, ok := strings.Cut(code, "-")
+	if !ok || len(digits) < 3 {
+		return 0
+	}
+	status, err := strconv.Atoi(digits[:3])
+	if err != nil || status < 100 || status > 599 {
+		return 0
+	}
+	return status
+}
+
+// classifyMessage classifies an error by its message. It is only used for errors without a code or an HTTP status
+// identifying them, such as the errors of failed tasks, and returns nil if the message is not recognized.
+func classifyMessage(message string) error {
+	message = strings.ToLower(message)
+	switch {
+	case strings.Contains(message, "no ip addresses exist with context"):
+		return errNotReserved
+	case strings.Contains(message, "already reserved"),
+		strings.Contains(message, "already in use"):
+		return ErrAddressAlreadyReserved
+	default:
+		return nil
+	}
+}
+
+// statusCode parses the HTTP status code from a status line such as "404 Not Found".
+func statusCode(status string) int {
+	code, _, _ := strings.Cut(status, " ")
+	parsed, err := strconv.Atoi(code)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
+// apiErrorMessages returns the messages of a v4 API error response body. Bodies that are not an error response
+// with a list of messages, e.g. schema validation errors, return no messages.
+func apiErrorMessages(body []byte) []networkingerror.AppMessage {
+	var response struct {
+		Data struct {
+			Error json.RawMessage `json:"error"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil
+	}
+	var messages []networkingerror.AppMessage
+	if err := json.Unmarshal(response.Data.Error, &messages); err != nil {
+		return nil
+	}
+	return messages
+}
+
+func isTransientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"syscall"
+
+	"github.com/google/uuid"
+	networkingclient "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/client"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func apiError(status, code, message string) error {
+	return networkingclient.GenericOpenAPIError{
+		Status: status,
+		Body: fmt.Appendf(nil,
+			`{"data":{"$objectType":"networking.v4.error.ErrorResponse","error":[{"code":%q,"message":%q}]}}`,
+			code, message,
+		),
+	}
+}
+
+var _ = DescribeTable("classifyError",
+	func(err, notFound, expectedKind error) {
+		classified := classifyError(fmt.Errorf("request failed: %w", err), notFound)
+		if expectedKind == nil {
+			Expect(errors.As(classified, new(*Error))).To(BeFalse())
+			return
+		}
+		Expect(classified).To(MatchError(expectedKind))
+		Expect(classified.Error()).To(ContainSubstring("request failed"))
+	},
+	Entry("unauthorized response",
+		apiError("401 Unauthorized", "NET-40100", "Authentication failed"), nil, ErrUnauthorized),
+	Entry("forbidden response",
+		apiError("403 Forbidden", "NET-40300", "Access denied"), nil, ErrUnauthorized),
+	Entry("not found response of a subnet",
+		apiError("404 Not Found", "NET-40400", "Subnet not found"), ErrSubnetNotFound, ErrSubnetNotFound),
+	Entry("not found response of an unknown object",
+		apiError("404 Not Found", "NET-40400", "Not found"), nil, nil),
+	Entry("rate limited response",
+		apiError("429 Too Many Requests", "NET-42900", "Too many requests"), nil, ErrTransient),
+	Entry("unavailable response",
+		apiError("503 Service Unavailable", "", "Service unavailable"), nil, ErrTransient),
+	Entry("conflict code of an IP reserved by others",
+		apiError("409 Conflict", "NET-40900", "Cannot reserve IP 10.0.0.10"), nil, ErrAddressAlreadyReserved),
+	Entry("message code without an HTTP status",
+		apiError("", "NET-40100", "Authentication failed"), nil, ErrUnauthorized),
+	Entry("message code taking precedence over the HTTP status",
+		apiError("500 Internal Server Error", "NET-50300", "Service unavailable"), nil, ErrTransient),
+	Entry("message code taking precedence over the message",
+		apiError("403 Forbidden", "NET-40300", "No free IP addresses available"), nil, ErrUnauthorized),
+	Entry("bad request code of an exhausted IP pool, classified by the IP usage of the subnet",
+		apiError("400 Bad Request", "NET-40000", "Not enough free IPs in the IP pools"), nil, nil),
+	Entry("exhausted IP pool of a failed task, classified by the IP usage of the subnet",
+		errors.New("task failed: No free IP addresses available in the subnet"), nil, nil),
+	Entry("already reserved IP of a failed task",
+		errors.New("task failed: IP address 10.0.0.10 is already reserved"), nil, ErrAddressAlreadyReserved),
+	Entry("no IPs reserved for the client context of a failed task",
+		errors.New("task failed: No IP addresses exist with context: abc"), nil, errNotReserved),
+	Entry("refused connection",
+		&url.Error{Op: "Get", URL: "https://prism.example.com:9440", Err: syscall.ECONNREFUSED}, nil, ErrTransient),
+	Entry("unknown error", errors.New("task failed"), nil, nil),
+)
+
+// v4ErrorBody returns an error response body of the v4 networking API with a single message, including the
+// metadata the API adds to its messages.
+func v4ErrorBody(code, message string) []byte {
+	return fmt.Appendf(nil, `{
+  "data": {
+    "$objectType": "networking.v4.error.ErrorResponse",
+    "$reserved": {"$fv": "v4.r0"},
+    "error": [
+      {
+        "$objectType": "networking.v4.error.AppMessage",
+        "$reserved": {"$fv": "v4.r0"},
+        "code": %q,
+        "message": %q,
+        "locale": "en_US",
+        "severity": "ERROR",
+        "errorGroup": "NETWORKING",
+        "argumentsMap": {}
+      }
+    ]
+  },
+  "$reserved": {"$fv": "v4.r0"},
+  "$objectType": "networking.v4.error.ErrorResponse"
+}`, code, message)
+}
+
+// The responses are classified as ReserveIPsInSubnet does for a subnet without free IPs.
+var _ = DescribeTable("classifyError of v4 API error responses",
+	func(status string, body []byte, expectedKind error) {
+		err := networkingclient.GenericOpenAPIError{Status: status, Body: body}
+		subnet := NewSubnet(uuid.New(), 24, WithSubnetIPUsage(SubnetIPUsage{Assigned: 10, Free: 0}))
+
+		classified := poolExhaustedError(classifyError(err, ErrSubnetNotFound), subnet, 1)
+		Expect(classified).To(MatchError(expectedKind))
+		for _, kind := range []error{
+			ErrUnauthorized, ErrSubnetNotFound, ErrTransient, ErrPoolExhausted, ErrAddressAlreadyReserved,
+		} {
+			if kind != expectedKind {
+				Expect(classified).NotTo(MatchError(kind))
+			}
+		}
+	},
+	Entry("unauthorized",
+		"401 Unauthorized", v4ErrorBody("NET-40100", "Authentication failed"), ErrUnauthorized),
+	Entry("forbidden",
+		"403 Forbidden", v4ErrorBody("NET-40300", "User is not authorized to reserve IPs"), ErrUnauthorized),
+	Entry("not found",
+		"404 Not Found", v4ErrorBody("NET-40400", "Subnet with extID 0e2f4c5a not found"), ErrSubnetNotFound),
+	Entry("throttled",
+		"429 Too Many Requests", v4ErrorBody("NET-42900", "Rate limit exceeded"), ErrTransient),
+	Entry("exhausted",
+		"400 Bad Request", v4ErrorBody("NET-40000", "Failed to reserve IPs in subnet 0e2f4c5a"), ErrPoolExhausted),
+	Entry("already reserved",
+		"409 Conflict", v4ErrorBody("NET-40900", "IP 10.0.0.10 is already reserved"), ErrAddressAlreadyReserved),
+	Entry("code taking precedence over the status",
+		"500 Internal Server Error", v4ErrorBody("NET-40900", "IP 10.0.0.10 is in use"), ErrAddressAlreadyReserved),
+	Entry("status without a code",
+		"401 Unauthorized", v4ErrorBody("", "Authentication failed"), ErrUnauthorized),
+	Entry("code taking precedence over the message",
+		"400 Bad Request", v4ErrorBody("NET-40400", "IP 10.0.0.10 is already reserved"), ErrSubnetNotFound),
+	Entry("message without a code or status",
+		"", v4ErrorBody("", "IP 10.0.0.10 is already reserved"), ErrAddressAlreadyReserved),
+)
+
+var _ = DescribeTable("poolExhaustedError",
+	func(err error, free, count int64, expectedKind error) {
+		subnet := NewSubnet(uuid.New(), 24, WithSubnetIPUsage(SubnetIPUsage{Assigned: 10 - free, Free: free}))
+
+		exhausted := poolExhaustedError(err, subnet, count)
+		if expectedKind == nil {
+			Expect(exhausted).To(Equal(err))
+			return
+		}
+		Expect(exhausted).To(MatchError(expectedKind))
+		Expect(exhausted).To(MatchError(err.Error()))
+	},
+	Entry("bad request of a subnet without free IPs",
+		apiError("400 Bad Request", "NET-40000", "Reservation failed"), int64(0), int64(1), ErrPoolExhausted),
+	Entry("failed task of a subnet with fewer free IPs than requested",
+		errors.New("task failed"), int64(1), int64(2), ErrPoolExhausted),
+	Entry("bad request of a subnet with enough free IPs",
+		apiError("400 Bad Request", "NET-40000", "Reservation failed"), int64(2), int64(2), nil),
+	Entry("classified error of a subnet without free IPs",
+		classifyError(apiError("403 Forbidden", "NET-40300", "Access denied"), nil), int64(0), int64(1), nil),
+)
+
+var _ = DescribeTable("codeStatus",
+	func(code string, expected int) {
+		Expect(codeStatus(code)).To(Equal(expected))
+	},
+	Entry("five digit code", "NET-40900", 409),
+	Entry("three digit code", "NET-404", 404),
+	Entry("no code", "", 0),
+	Entry("code without digits", "NET-CONFLICT", 0),
+	Entry("code out of the HTTP status range", "NET-99900", 0),
+)
+
+var _ = Describe("Error", func() {
+	It("should expose the details of the API response", func() {
+		err := classifyError(apiError("403 Forbidden", "NET-40300", "Access denied"), nil)
+
+		var classified *Error
+		Expect(errors.As(err, &classified)).To(BeTrue())
+		Expect(classified.StatusCode).To(Equal(403))
+		Expect(classified.Code).To(Equal("NET-40300"))
+		Expect(errors.As(err, new(networkingclient.GenericOpenAPIError))).To(BeTrue())
+	})
+
+	It("should keep the message of errors created by the client", func() {
+		err := fmt.Errorf("failed to get subnet: %w", newError(ErrAmbiguousName, "multiple subnets (%d) found", 2))
+
+		Expect(err).To(MatchError(ErrAmbiguousName))
+		Expect(err).To(MatchError("failed to get subnet: multiple subnets (2) found"))
+	})
+})
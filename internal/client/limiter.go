@@ -0,0 +1,127 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// Limits are the limits on the requests sent to a single Prism Central, shared by all pools referencing it.
+type Limits struct {
+	// QPS is the maximum sustained rate of requests per second. Zero disables rate limiting.
+	QPS float64
+
+	// Burst is the maximum number of requests sent at once above the sustained rate.
+	Burst int
+
+	// MaxInFlight is the maximum number of concurrent requests. Zero disables the limit.
+	MaxInFlight int
+}
+
+// DefaultLimits are the default limits on the requests sent to a single Prism Central.
+var DefaultLimits = Limits{
+	QPS:         20,
+	Burst:       40,
+	MaxInFlight: 10,
+}
+
+var limiters = newEndpointLimiters(DefaultLimits)
+
+// SetLimits sets the limits on the requests sent to each Prism Central.
+func SetLimits(limits Limits) {
+	limiters.setLimits(limits)
+}
+
+// endpointLimiters holds the limiter of each Prism Central endpoint.
+type endpointLimiters struct {
+	mu         sync.Mutex
+	limits     Limits
+	byEndpoint map[string]*endpointLimiter
+}
+
+func newEndpointLimiters(limits Limits) *endpointLimiters {
+	return &endpointLimiters{
+		limits:     limits,
+		byEndpoint: map[string]*endpointLimiter{},
+	}
+}
+
+func (l *endpointLimiters) setLimits(limits Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+	clear(l.byEndpoint)
+}
+
+// get returns the limiter of the Prism Central at the endpoint, in host:port form.
+func (l *endpointLimiters) get(endpoint string) *endpointLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limiter, ok := l.byEndpoint[endpoint]
+	if !ok {
+		limiter = newEndpointLimiter(endpoint, l.limits)
+		l.byEndpoint[endpoint] = limiter
+	}
+	return limiter
+}
+
+// endpointLimiter limits the rate and concurrency of the requests sent to a Prism Central. Requests exceeding the
+// limits are rejected with ErrThrottled rather than queued, so that the caller is requeued with backoff instead of
+// blocking a worker.
+type endpointLimiter struct {
+	endpoint string
+	limits   Limits
+	tokens   *rate.Limiter
+	inFlight chan struct{}
+}
+
+func newEndpointLimiter(endpoint string, limits Limits) *endpointLimiter {
+	limiter := &endpointLimiter{
+		endpoint: endpoint,
+		limits:   limits,
+	}
+	if limits.QPS > 0 {
+		limiter.tokens = rate.NewLimiter(rate.Limit(limits.QPS), max(limits.Burst, 1))
+	}
+	if limits.MaxInFlight > 0 {
+		limiter.inFlight = make(chan struct{}, limits.MaxInFlight)
+	}
+	return limiter
+}
+
+// acquire admits a request to the Prism Central, returning a function that must be called once the request
+// completes. An error of kind ErrThrottled is returned if the request exceeds the limits.
+func (l *endpointLimiter) acquire() (func(), error) {
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		default:
+			return nil, newError(
+				ErrThrottled,
+				"too many concurrent requests to Prism Central %s (limit %d)",
+				l.endpoint,
+				l.limits.MaxInFlight,
+			)
+		}
+	}
+	release := func() {
+		if l.inFlight != nil {
+			<-l.inFlight
+		}
+	}
+
+	if l.tokens != nil && !l.tokens.Allow() {
+		release()
+		return nil, newError(
+			ErrThrottled,
+			"rate limit of %g requests per second to Prism Central %s exceeded",
+			l.limits.QPS,
+			l.endpoint,
+		)
+	}
+
+	return sync.OnceFunc(release), nil
+}
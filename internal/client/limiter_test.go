@@ -0,0 +1,85 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("endpointLimiter", func() {
+	const endpoint = "prism.example.com:9440"
+
+	It("should reject requests above the concurrency limit until a request completes", func() {
+		limiter := newEndpointLimiter(endpoint, Limits{MaxInFlight: 2})
+
+		release1, err := limiter.acquire()
+		Expect(err).NotTo(HaveOccurred())
+		release2, err := limiter.acquire()
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = limiter.acquire()
+		Expect(err).To(MatchError(ErrThrottled))
+		Expect(err).To(MatchError(ContainSubstring(endpoint)))
+
+		release1()
+		// Releasing twice must not free another slot.
+		release1()
+		release3, err := limiter.acquire()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = limiter.acquire()
+		Expect(err).To(MatchError(ErrThrottled))
+
+		release2()
+		release3()
+	})
+
+	It("should reject requests above the rate limit without holding a concurrency slot", func() {
+		limiter := newEndpointLimiter(endpoint, Limits{QPS: 0.001, Burst: 2, MaxInFlight: 1})
+
+		release, err := limiter.acquire()
+		Expect(err).NotTo(HaveOccurred())
+		release()
+		release, err = limiter.acquire()
+		Expect(err).NotTo(HaveOccurred())
+		release()
+
+		_, err = limiter.acquire()
+		Expect(err).To(MatchError(ErrThrottled))
+		Expect(limiter.inFlight).To(BeEmpty())
+	})
+
+	It("should not limit requests if the limits are zero", func() {
+		limiter := newEndpointLimiter(endpoint, Limits{})
+		for range 100 {
+			_, err := limiter.acquire()
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
+
+var _ = Describe("endpointLimiters", func() {
+	It("should share a limiter per endpoint", func() {
+		limiters := newEndpointLimiters(Limits{MaxInFlight: 1})
+
+		Expect(limiters.get("pc1.example.com:9440")).To(BeIdenticalTo(limiters.get("pc1.example.com:9440")))
+
+		_, err := limiters.get("pc1.example.com:9440").acquire()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = limiters.get("pc1.example.com:9440").acquire()
+		Expect(err).To(MatchError(ErrThrottled))
+		_, err = limiters.get("pc2.example.com:9440").acquire()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should apply new limits to all endpoints", func() {
+		limiters := newEndpointLimiters(Limits{MaxInFlight: 1})
+		previous := limiters.get("pc1.example.com:9440")
+
+		limiters.setLimits(Limits{MaxInFlight: 5})
+		current := limiters.get("pc1.example.com:9440")
+		Expect(current).NotTo(BeIdenticalTo(previous))
+		Expect(current.limits).To(Equal(Limits{MaxInFlight: 5}))
+	})
+})
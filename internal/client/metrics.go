@@ -0,0 +1,99 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// OperationReserveIPs is the operation label value for ReserveIPs requests.
+	OperationReserveIPs = "ReserveIPs"
+	// OperationUnreserveIPs is the operation label value for UnreserveIPs requests.
+	OperationUnreserveIPs = "UnreserveIPs"
+	// OperationGetSubnet is the operation label value for GetSubnet requests.
+	OperationGetSubnet = "GetSubnet"
+	// OperationGetCluster is the operation label value for GetCluster requests.
+	OperationGetCluster = "GetCluster"
+
+	// OutcomeSuccess is the outcome label value for successful requests.
+	OutcomeSuccess = "success"
+	// OutcomeError is the outcome label value for failed requests.
+	OutcomeError = "error"
+)
+
+var (
+	prismRequestLabels = []string{"operation", "pool", "subnet", "outcome"}
+
+	prismRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "caipamx_prism_requests_total",
+			Help: "Total number of Prism Central IPAM requests by operation, pool, subnet and outcome.",
+		},
+		prismRequestLabels,
+	)
+
+	prismRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "caipamx_prism_request_duration_seconds",
+			Help: "Latency of Prism Central IPAM requests by operation, pool, subnet and outcome.",
+			// Reservations block on a Prism Central task, so allow for requests taking tens of seconds.
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		},
+		prismRequestLabels,
+	)
+
+	prismCircuitBreakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "caipamx_prism_circuit_breaker_state",
+			Help: "State of the circuit breaker of each Prism Central endpoint: 0 closed, 1 half-open, 2 open.",
+		},
+		[]string{"endpoint"},
+	)
+)
+
+func init() { //nolint:gochecknoinits // Metrics must be registered before the metrics server is started.
+	metrics.Registry.MustRegister(prismRequestsTotal, prismRequestDuration, prismCircuitBreakerState)
+}
+
+type poolContextKey struct{}
+
+// WithPool returns a copy of ctx that associates Prism Central requests made with it with the given pool in
+// the request metrics.
+func WithPool(ctx context.Context, pool string) context.Context {
+	return context.WithValue(ctx, poolContextKey{}, pool)
+}
+
+// poolFromContext returns the pool associated with ctx by WithPool, or an empty string.
+func poolFromContext(ctx context.Context) string {
+	pool, _ := ctx.Value(poolContextKey{}).(string)
+	return pool
+}
+
+// observeRequest records the outcome and latency of a Prism Central request started at start. It is intended
+// to be deferred with a pointer to the named error result of the request.
+func observeRequest(ctx context.Context, operation, subnet string, start time.Time, err *error) {
+	outcome := OutcomeSuccess
+	if *err != nil {
+		outcome = OutcomeError
+	}
+
+	labels := prometheus.Labels{
+		"operation": operation,
+		"pool":      poolFromContext(ctx),
+		"subnet":    subnet,
+		"outcome":   outcome,
+	}
+	prismRequestsTotal.With(labels).Inc()
+	prismRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+}
+
+// observeCircuitState records the state of the circuit breaker of a Prism Central endpoint.
+func observeCircuitState(endpoint string, state CircuitState) {
+	prismCircuitBreakerState.WithLabelValues(endpoint).Set(float64(state))
+}
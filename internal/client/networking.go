@@ -5,9 +5,11 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/netip"
-	"strings"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	commonapi "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/common/v1/config"
@@ -133,12 +135,26 @@ type NetworkingClient interface {
 		subnet string,
 		opts ReserveIPOpts,
 	) ([]netip.Addr, error)
+	// ReserveIPsInSubnet reserves IPs in an already resolved subnet, saving the subnet lookup of ReserveIPs.
+	ReserveIPsInSubnet(
+		ctx context.Context,
+		reserveType IPReservationTypeFunc,
+		subnet *Subnet,
+		opts ReserveIPOpts,
+	) ([]netip.Addr, error)
 	UnreserveIPs(
 		ctx context.Context,
 		unreserveType IPUnreservationTypeFunc,
 		subnet string,
 		opts UnreserveIPOpts,
 	) ([]netip.Addr, error)
+	// UnreserveIPsInSubnet unreserves IPs in an already resolved subnet, saving the subnet lookup of UnreserveIPs.
+	UnreserveIPsInSubnet(
+		ctx context.Context,
+		unreserveType IPUnreservationTypeFunc,
+		subnet *Subnet,
+	) ([]netip.Addr, error)
+	ListReservedIPs(ctx context.Context, subnet string, opts ListReservedIPsOpts) ([]ReservedIP, error)
 	GetSubnet(ctx context.Context, subnet string, opts GetSubnetOpts) (*Subnet, error)
 }
 
@@ -162,6 +178,14 @@ func (n *networkingClient) ReserveIPs(
 		return nil, fmt.Errorf("failed to get subnet %s: %w", subnet, err)
 	}
 
+	return n.ReserveIPsInSubnet(ctx, reserveType, apiSubnet, opts)
+}
+
+func (n *networkingClient) ReserveIPsInSubnet(
+	ctx context.Context, reserveType IPReservationTypeFunc, subnet *Subnet, opts ReserveIPOpts,
+) (_ []netip.Addr, reterr error) {
+	defer observeRequest(ctx, OperationReserveIPs, subnet.ExtID().String(), time.Now(), &reterr)
+
 	reservation := internalReserveSpec{}
 	reserveType(&reservation)
 
@@ -172,13 +196,22 @@ func (n *networkingClient) ReserveIPs(
 	// ReserveIpsBySubnetId submits the reservation task and blocks until it
 	// completes, returning the reserved IP addresses from the task completion
 	// details.
+	done, err := n.admit()
+	if err != nil {
+		return nil, err
+	}
 	reservedIPs, err := n.v4Client.Subnets.ReserveIpsBySubnetId(
 		ctx,
-		apiSubnet.ExtID().String(),
+		subnet.ExtID().String(),
 		&reservation.IpReserveSpec,
 	)
+	done(err)
 	if err != nil {
-		return nil, fmt.Errorf("failed to reserve IP in subnet %s: %w", subnet, err)
+		err = n.subnetError(subnet, err)
+		if reservation.Count != nil {
+			err = n.reservationError(ctx, subnet, *reservation.Count, err)
+		}
+		return nil, fmt.Errorf("failed to reserve IP in subnet %s: %w", subnet.ExtID(), err)
 	}
 
 	if len(reservedIPs) == 0 {
@@ -304,6 +337,14 @@ func (n *networkingClient) UnreserveIPs(
 		return nil, fmt.Errorf("failed to get subnet %s: %w", subnet, err)
 	}
 
+	return n.UnreserveIPsInSubnet(ctx, unreserveType, apiSubnet)
+}
+
+func (n *networkingClient) UnreserveIPsInSubnet(
+	ctx context.Context, unreserveType IPUnreservationTypeFunc, subnet *Subnet,
+) (_ []netip.Addr, reterr error) {
+	defer observeRequest(ctx, OperationUnreserveIPs, subnet.ExtID().String(), time.Now(), &reterr)
+
 	unreservation := internalUnreserveSpec{}
 	unreserveType(&unreservation)
 
@@ -311,18 +352,24 @@ func (n *networkingClient) UnreserveIPs(
 	// completes, and returns the IPs the server released. This is useful when
 	// unreserving by client context, where the caller does not know up front
 	// which IPs will be released.
+	done, err := n.admit()
+	if err != nil {
+		return nil, err
+	}
 	unreservedIPs, err := n.v4Client.Subnets.UnreserveIpsBySubnetId(
 		ctx,
-		apiSubnet.ExtID().String(),
+		subnet.ExtID().String(),
 		&unreservation.IpUnreserveSpec,
 	)
+	done(err)
 	if err != nil {
+		err = n.subnetError(subnet, err)
 		// Unreserving an IP that was never reserved (or already released) is
 		// not an error from our perspective; the desired end state is reached.
-		if unreservationAlreadyReleased(err) {
+		if errors.Is(err, errNotReserved) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to unreserve IP in subnet %s: %w", subnet, err)
+		return nil, fmt.Errorf("failed to unreserve IP in subnet %s: %w", subnet.ExtID(), err)
 	}
 
 	ips := make([]netip.Addr, 0, len(unreservedIPs))
@@ -337,21 +384,235 @@ func (n *networkingClient) UnreserveIPs(
 	return ips, nil
 }
 
-func unreservationAlreadyReleased(err error) bool {
-	return strings.Contains(err.Error(), "No IP addresses exist with context:")
+// subnetError classifies an error returned by the API for a request on the subnet. If the subnet no longer exists,
+// it is dropped from the resolution cache so that it is resolved again by the next request.
+func (n *networkingClient) subnetError(subnet *Subnet, err error) error {
+	err = classifyError(err, ErrSubnetNotFound)
+	if errors.Is(err, ErrSubnetNotFound) {
+		n.client.resolutions.invalidateSubnet(n.client.endpoint, subnet.ExtID())
+	}
+	return err
+}
+
+// reservationError classifies an error of a reservation of count IPs in the subnet that could not be classified
+// otherwise as ErrPoolExhausted if the subnet has fewer free IPs than requested, looking up its current IP usage.
+func (n *networkingClient) reservationError(ctx context.Context, subnet *Subnet, count int64, err error) error {
+	if errors.As(err, new(*Error)) {
+		return err
+	}
+	refreshed, lookupErr := n.getSubnet(ctx, subnet.ExtID().String(), GetSubnetOpts{})
+	if lookupErr != nil {
+		return err
+	}
+	return poolExhaustedError(err, refreshed, count)
+}
+
+// ReservedIP is an IP address reserved in a subnet.
+type ReservedIP struct {
+	// Address is the reserved IP address.
+	Address netip.Addr
+
+	// ClientContext is the client context the IP address was reserved with, if any.
+	ClientContext string
+}
+
+// ListReservedIPsOpts holds optional configuration for listing reserved IP addresses.
+type ListReservedIPsOpts struct {
+	// Cluster is the name of the cluster where the subnet is located. Only required if using the subnet
+	// name rather than the extID.
+	Cluster string
+
+	// ClientContext restricts the listed IP addresses to those reserved with this client context. If empty,
+	// all reserved IP addresses in the subnet are listed.
+	ClientContext string
+}
+
+// reservedIPsPageSize is the number of reserved IPs listed per request, the maximum page size of the v4 API.
+const reservedIPsPageSize = 100
+
+func (n *networkingClient) ListReservedIPs(
+	ctx context.Context, subnet string, opts ListReservedIPsOpts,
+) ([]ReservedIP, error) {
+	apiSubnet, err := n.GetSubnet(ctx, subnet, GetSubnetOpts{Cluster: opts.Cluster})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subnet %s: %w", subnet, err)
+	}
+
+	var filterOpts []converged.ODataOption
+	if opts.ClientContext != "" {
+		filterOpts = append(filterOpts, odataFilter{}.eq("clientContext", opts.ClientContext).option())
+	}
+
+	// List all pages of reserved IPs, as callers rely on the list being complete, e.g. to adopt the reservations of
+	// a claim or to detect the IPs reserved by others.
+	var reservedIPs []networkingapi.ReservedIp
+	for page := 0; ; page++ {
+		done, err := n.admit()
+		if err != nil {
+			return nil, err
+		}
+		pageIPs, err := n.v4Client.Subnets.ListReservedIpsBySubnetId(
+			ctx,
+			apiSubnet.ExtID().String(),
+			append(
+				slices.Clone(filterOpts),
+				converged.WithPage(page),
+				converged.WithLimit(reservedIPsPageSize),
+			)...,
+		)
+		done(err)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to list reserved IPs in subnet %s: %w", subnet, n.subnetError(apiSubnet, err),
+			)
+		}
+		reservedIPs = append(reservedIPs, pageIPs...)
+		if len(pageIPs) < reservedIPsPageSize {
+			break
+		}
+	}
+
+	ips := make([]ReservedIP, 0, len(reservedIPs))
+	for _, reservedIP := range reservedIPs {
+		clientContext := ptr.Deref(reservedIP.ClientContext, "")
+		// Filter again in case the server ignored the filter.
+		if opts.ClientContext != "" && clientContext != opts.ClientContext {
+			continue
+		}
+		addr, ok, err := reservedIPAddress(&reservedIP)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		ips = append(ips, ReservedIP{Address: addr, ClientContext: clientContext})
+	}
+
+	return ips, nil
+}
+
+// reservedIPAddress returns the IPv4 or IPv6 address of a reserved IP, or false if it has neither. The v4.2 networking
+// model only has a field for IPv4 addresses, so IPv6 addresses are read from the unknown fields of the reserved IP.
+func reservedIPAddress(reservedIP *networkingapi.ReservedIp) (netip.Addr, bool, error) {
+	address := ptr.Deref(reservedIP.Ipv4Address, "")
+	if address == "" {
+		address, _ = reservedIP.UnknownFields_["ipv6Address"].(string)
+	}
+	if address == "" {
+		return netip.Addr{}, false, nil
+	}
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return netip.Addr{}, false, fmt.Errorf("failed to parse reserved IP %q: %w", address, err)
+	}
+	return addr, true, nil
 }
 
 // Subnet represents a subnet in the networking API.
 type Subnet struct {
-	extID  uuid.UUID
-	prefix int32
+	extID         uuid.UUID
+	prefix        int32
+	ipv4Prefix    *int32
+	ipv6Prefix    *int32
+	clusterExtID  uuid.UUID
+	pools         *netipx.IPSet
+	ipUsage       *SubnetIPUsage
+	ipv4Gateway   netip.Addr
+	ipv6Gateway   netip.Addr
+	dnsServers    []netip.Addr
+	searchDomains []string
+	domainName    string
+}
+
+// SubnetIPUsage holds the IP usage of a subnet as reported by Prism Central.
+type SubnetIPUsage struct {
+	// Assigned is the number of IPs assigned in the subnet.
+	Assigned int64
+	// Free is the number of IPs free in the subnet.
+	Free int64
+}
+
+// SubnetOption configures optional properties of a Subnet.
+type SubnetOption func(*Subnet)
+
+// WithSubnetIPv4Prefix sets the prefix length of the IPv4 configuration of the subnet.
+func WithSubnetIPv4Prefix(prefix int32) SubnetOption {
+	return func(s *Subnet) {
+		s.ipv4Prefix = &prefix
+	}
+}
+
+// WithSubnetIPv6Prefix sets the prefix length of the IPv6 configuration of the subnet.
+func WithSubnetIPv6Prefix(prefix int32) SubnetOption {
+	return func(s *Subnet) {
+		s.ipv6Prefix = &prefix
+	}
+}
+
+// WithSubnetClusterExtID sets the extID of the cluster the subnet belongs to.
+func WithSubnetClusterExtID(extID uuid.UUID) SubnetOption {
+	return func(s *Subnet) {
+		s.clusterExtID = extID
+	}
+}
+
+// WithSubnetPools sets the IP pool ranges configured on the subnet.
+func WithSubnetPools(pools *netipx.IPSet) SubnetOption {
+	return func(s *Subnet) {
+		s.pools = pools
+	}
+}
+
+// WithSubnetIPUsage sets the IP usage of the subnet.
+func WithSubnetIPUsage(usage SubnetIPUsage) SubnetOption {
+	return func(s *Subnet) {
+		s.ipUsage = &usage
+	}
 }
 
-func NewSubnet(extID uuid.UUID, prefix int32) *Subnet {
-	return &Subnet{
+// WithSubnetGateway sets the default gateway of the subnet configuration matching the IP family of the given
+// address.
+func WithSubnetGateway(gateway netip.Addr) SubnetOption {
+	return func(s *Subnet) {
+		if gateway.Is4() {
+			s.ipv4Gateway = gateway
+		} else {
+			s.ipv6Gateway = gateway
+		}
+	}
+}
+
+// WithSubnetDNSServers sets the DNS servers of the subnet.
+func WithSubnetDNSServers(servers ...netip.Addr) SubnetOption {
+	return func(s *Subnet) {
+		s.dnsServers = servers
+	}
+}
+
+// WithSubnetSearchDomains sets the domain search list of the subnet.
+func WithSubnetSearchDomains(domains ...string) SubnetOption {
+	return func(s *Subnet) {
+		s.searchDomains = domains
+	}
+}
+
+// WithSubnetDomainName sets the domain name of the subnet.
+func WithSubnetDomainName(domainName string) SubnetOption {
+	return func(s *Subnet) {
+		s.domainName = domainName
+	}
+}
+
+func NewSubnet(extID uuid.UUID, prefix int32, opts ...SubnetOption) *Subnet {
+	s := &Subnet{
 		extID:  extID,
 		prefix: prefix,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // ExtID returns the external ID of the subnet.
@@ -364,11 +625,84 @@ func (s *Subnet) Prefix() int32 {
 	return s.prefix
 }
 
+// PrefixFor returns the prefix length of the subnet configuration matching the IP family of the given address,
+// falling back to Prefix if the subnet has no configuration for that family.
+func (s *Subnet) PrefixFor(addr netip.Addr) int32 {
+	switch {
+	case addr.Is4() && s.ipv4Prefix != nil:
+		return *s.ipv4Prefix
+	case addr.Is6() && s.ipv6Prefix != nil:
+		return *s.ipv6Prefix
+	default:
+		return s.prefix
+	}
+}
+
+// HasIPConfigFor returns whether the subnet has an IP configuration matching the IP family of the given address.
+// Subnets without any IP configuration, e.g. subnets created with NewSubnet without prefix options, are assumed
+// to match both IP families.
+func (s *Subnet) HasIPConfigFor(addr netip.Addr) bool {
+	if s.ipv4Prefix == nil && s.ipv6Prefix == nil {
+		return true
+	}
+	if addr.Is4() {
+		return s.ipv4Prefix != nil
+	}
+	return s.ipv6Prefix != nil
+}
+
+// ClusterExtID returns the extID of the cluster the subnet belongs to, or uuid.Nil if the subnet is not
+// attached to a cluster, e.g. an overlay subnet.
+func (s *Subnet) ClusterExtID() uuid.UUID {
+	return s.clusterExtID
+}
+
+// Pools returns the IP pool ranges configured on the subnet. The returned set is empty if the subnet has no
+// IP pools configured.
+func (s *Subnet) Pools() *netipx.IPSet {
+	if s.pools == nil {
+		return &netipx.IPSet{}
+	}
+	return s.pools
+}
+
+// IPUsage returns the IP usage of the subnet, or nil if Prism Central did not report it.
+func (s *Subnet) IPUsage() *SubnetIPUsage {
+	return s.ipUsage
+}
+
+// GatewayFor returns the default gateway of the subnet configuration matching the IP family of the given address.
+// The returned address is invalid if the subnet has no default gateway for that family.
+func (s *Subnet) GatewayFor(addr netip.Addr) netip.Addr {
+	if addr.Is4() {
+		return s.ipv4Gateway
+	}
+	return s.ipv6Gateway
+}
+
+// DNSServers returns the DNS servers of the subnet.
+func (s *Subnet) DNSServers() []netip.Addr {
+	return s.dnsServers
+}
+
+// SearchDomains returns the domain search list of the subnet.
+func (s *Subnet) SearchDomains() []string {
+	return s.searchDomains
+}
+
+// DomainName returns the domain name of the subnet, or an empty string if none is configured.
+func (s *Subnet) DomainName() string {
+	return s.domainName
+}
+
 // GetSubnetOpts holds optional configuration for getting a subnet.
 type GetSubnetOpts struct {
 	// Cluster is the name of the cluster where the subnet is located. Only required if using the subnet
 	// name rather than the extID.
 	Cluster string
+
+	// Refresh gets the subnet from Prism Central even if it is cached, e.g. to get the current IP usage.
+	Refresh bool
 }
 
 func (n *networkingClient) GetSubnet(
@@ -376,25 +710,74 @@ func (n *networkingClient) GetSubnet(
 	subnetExtIDOrName string,
 	opts GetSubnetOpts,
 ) (*Subnet, error) {
+	key := resolutionKey{
+		endpoint: n.client.endpoint,
+		kind:     subnetResolution,
+		cluster:  opts.Cluster,
+		name:     subnetExtIDOrName,
+	}
+	if !opts.Refresh {
+		if subnet, ok := getCached[*Subnet](n.client.resolutions, key); ok {
+			return subnet, nil
+		}
+	}
+
+	subnet, err := n.getSubnet(ctx, subnetExtIDOrName, opts)
+	if err != nil {
+		if errors.Is(err, ErrSubnetNotFound) {
+			n.client.resolutions.delete(key)
+		}
+		return nil, err
+	}
+	n.client.resolutions.set(key, subnet)
+	return subnet, nil
+}
+
+func (n *networkingClient) getSubnet(
+	ctx context.Context,
+	subnetExtIDOrName string,
+	opts GetSubnetOpts,
+) (subnet *Subnet, reterr error) {
+	start := time.Now()
+	defer func() {
+		observeRequest(ctx, OperationGetSubnet, subnetLabel(subnet, subnetExtIDOrName), start, &reterr)
+	}()
+
 	var errs []error
 
 	subnetUUID, err := uuid.Parse(subnetExtIDOrName)
 	if err == nil {
-		subnet, errByExtID := n.getSubnetByExtID(ctx, subnetUUID)
+		subnetByExtID, errByExtID := n.getSubnetByExtID(ctx, subnetUUID)
 		if errByExtID == nil {
-			return subnet, nil
+			return subnetByExtID, nil
+		}
+		if errors.Is(errByExtID, ErrThrottled) {
+			return nil, fmt.Errorf("failed to get subnet %q: %w", subnetExtIDOrName, errByExtID)
 		}
 		errs = append(errs, errByExtID)
 	}
 
-	subnet, errByName := n.getSubnetByName(ctx, subnetExtIDOrName, opts)
+	subnetByName, errByName := n.getSubnetByName(ctx, subnetExtIDOrName, opts)
 	if errByName != nil {
 		errs = append(errs, errByName)
 		aggErr := kerrors.NewAggregate(errs)
 		return nil, fmt.Errorf("failed to get subnet %q: %w", subnetExtIDOrName, aggErr)
 	}
 
-	return subnet, nil
+	return subnetByName, nil
+}
+
+// subnetLabel returns the value of the subnet label of the request metrics for a lookup of subnetExtIDOrName,
+// so that all requests for a subnet share its extID as label. If the lookup failed, it is the extID the subnet
+// was looked up by, or empty if it was looked up by name.
+func subnetLabel(subnet *Subnet, subnetExtIDOrName string) string {
+	if subnet != nil {
+		return subnet.ExtID().String()
+	}
+	if subnetUUID, err := uuid.Parse(subnetExtIDOrName); err == nil {
+		return subnetUUID.String()
+	}
+	return ""
 }
 
 func (n *networkingClient) getSubnetByName(
@@ -402,36 +785,39 @@ func (n *networkingClient) getSubnetByName(
 	subnetName string,
 	opts GetSubnetOpts,
 ) (*Subnet, error) {
-	filter := fmt.Sprintf(`name eq '%s'`, subnetName)
+	filter := odataFilter{}.eq("name", subnetName)
 	if opts.Cluster != "" {
 		apiCluster, err := n.client.Cluster().GetCluster(ctx, opts.Cluster)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get cluster %s: %w", opts.Cluster, err)
 		}
 
-		filter += fmt.Sprintf(` and clusterReference eq '%s'`, apiCluster.ExtID())
+		filter = filter.eq("clusterReference", apiCluster.ExtID().String())
 	}
 
-	apiSubnets, err := n.v4Client.Subnets.List(
-		ctx,
-		converged.WithFilter(filter),
-	)
+	done, err := n.admit()
+	if err != nil {
+		return nil, err
+	}
+	apiSubnets, err := n.v4Client.Subnets.List(ctx, filter.option())
+	done(err)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to find subnet uuid for subnet %q: %w",
 			subnetName,
-			err,
+			classifyError(err, nil),
 		)
 	}
 	if apiSubnets == nil {
-		return nil, fmt.Errorf("no subnet found with name %q", subnetName)
+		return nil, newError(ErrSubnetNotFound, "no subnet found with name %q", subnetName)
 	}
 
 	if len(apiSubnets) == 0 {
-		return nil, fmt.Errorf("no subnet found with name %q", subnetName)
+		return nil, newError(ErrSubnetNotFound, "no subnet found with name %q", subnetName)
 	}
 	if len(apiSubnets) > 1 {
-		return nil, fmt.Errorf(
+		return nil, newError(
+			ErrAmbiguousName,
 			"multiple subnets (%d) found with name %q",
 			len(apiSubnets),
 			subnetName,
@@ -458,16 +844,21 @@ func (n *networkingClient) getSubnetByExtID(
 	ctx context.Context,
 	subnetExtID uuid.UUID,
 ) (*Subnet, error) {
+	done, err := n.admit()
+	if err != nil {
+		return nil, err
+	}
 	apiSubnet, err := n.v4Client.Subnets.Get(ctx, subnetExtID.String())
+	done(err)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to find subnet with extID %q: %w",
 			subnetExtID,
-			err,
+			classifyError(err, ErrSubnetNotFound),
 		)
 	}
 	if apiSubnet == nil {
-		return nil, fmt.Errorf("no subnet found with extID %q", subnetExtID)
+		return nil, newError(ErrSubnetNotFound, "no subnet found with extID %q", subnetExtID)
 	}
 
 	if apiSubnet.ExtId == nil {
@@ -495,7 +886,187 @@ func newSubnetFromAPI(apiSubnet *networkingapi.Subnet, description string) (*Sub
 		return nil, err
 	}
 
-	return NewSubnet(subnetUUID, prefix), nil
+	pools, err := subnetPools(apiSubnet, description)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []SubnetOption{WithSubnetPools(pools)}
+
+	for _, ipConfig := range apiSubnet.IpConfig {
+		if ipConfig.Ipv4 != nil && ipConfig.Ipv4.IpSubnet != nil &&
+			ipConfig.Ipv4.IpSubnet.PrefixLength != nil {
+			ipv4Prefix, err := prefixLengthToInt32(*ipConfig.Ipv4.IpSubnet.PrefixLength, description)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithSubnetIPv4Prefix(ipv4Prefix))
+		}
+		if ipConfig.Ipv6 != nil && ipConfig.Ipv6.IpSubnet != nil &&
+			ipConfig.Ipv6.IpSubnet.PrefixLength != nil {
+			ipv6Prefix, err := prefixLengthToInt32(*ipConfig.Ipv6.IpSubnet.PrefixLength, description)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, WithSubnetIPv6Prefix(ipv6Prefix))
+		}
+	}
+
+	gatewayOpts, err := subnetGateways(apiSubnet, description)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, gatewayOpts...)
+
+	dhcpOpts, err := subnetDHCPOptions(apiSubnet, description)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, dhcpOpts...)
+
+	if apiSubnet.ClusterReference != nil && *apiSubnet.ClusterReference != "" {
+		clusterUUID, err := uuid.Parse(*apiSubnet.ClusterReference)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse cluster reference %q for %s: %w",
+				*apiSubnet.ClusterReference,
+				description,
+				err,
+			)
+		}
+		opts = append(opts, WithSubnetClusterExtID(clusterUUID))
+	}
+
+	if apiSubnet.IpUsage != nil {
+		opts = append(opts, WithSubnetIPUsage(SubnetIPUsage{
+			Assigned: ptr.Deref(apiSubnet.IpUsage.NumAssignedIPs, 0),
+			Free:     ptr.Deref(apiSubnet.IpUsage.NumFreeIPs, 0),
+		}))
+	}
+
+	return NewSubnet(subnetUUID, prefix, opts...), nil
+}
+
+func subnetGateways(apiSubnet *networkingapi.Subnet, description string) ([]SubnetOption, error) {
+	var opts []SubnetOption
+	for _, ipConfig := range apiSubnet.IpConfig {
+		var gateways []*string
+		if ipConfig.Ipv4 != nil && ipConfig.Ipv4.DefaultGatewayIp != nil {
+			gateways = append(gateways, ipConfig.Ipv4.DefaultGatewayIp.Value)
+		}
+		if ipConfig.Ipv6 != nil && ipConfig.Ipv6.DefaultGatewayIp != nil {
+			gateways = append(gateways, ipConfig.Ipv6.DefaultGatewayIp.Value)
+		}
+		for _, gateway := range gateways {
+			if gateway == nil || *gateway == "" {
+				continue
+			}
+			addr, err := netip.ParseAddr(*gateway)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to parse default gateway %q for %s: %w",
+					*gateway,
+					description,
+					err,
+				)
+			}
+			opts = append(opts, WithSubnetGateway(addr))
+		}
+	}
+	return opts, nil
+}
+
+func subnetDHCPOptions(apiSubnet *networkingapi.Subnet, description string) ([]SubnetOption, error) {
+	dhcpOptions := apiSubnet.DhcpOptions
+	if dhcpOptions == nil {
+		return nil, nil
+	}
+
+	var dnsServers []netip.Addr
+	for _, server := range dhcpOptions.DomainNameServers {
+		var value *string
+		switch {
+		case server.Ipv4 != nil:
+			value = server.Ipv4.Value
+		case server.Ipv6 != nil:
+			value = server.Ipv6.Value
+		}
+		if value == nil || *value == "" {
+			continue
+		}
+		addr, err := netip.ParseAddr(*value)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse DNS server %q for %s: %w",
+				*value,
+				description,
+				err,
+			)
+		}
+		dnsServers = append(dnsServers, addr)
+	}
+
+	return []SubnetOption{
+		WithSubnetDNSServers(dnsServers...),
+		WithSubnetSearchDomains(dhcpOptions.SearchDomains...),
+		WithSubnetDomainName(ptr.Deref(dhcpOptions.DomainName, "")),
+	}, nil
+}
+
+func subnetPools(apiSubnet *networkingapi.Subnet, description string) (*netipx.IPSet, error) {
+	builder := &netipx.IPSetBuilder{}
+	for _, ipConfig := range apiSubnet.IpConfig {
+		if ipConfig.Ipv4 != nil {
+			for _, pool := range ipConfig.Ipv4.PoolList {
+				if pool.StartIp == nil || pool.EndIp == nil {
+					continue
+				}
+				ipRange, err := parseIPRange(
+					ptr.Deref(pool.StartIp.Value, ""), ptr.Deref(pool.EndIp.Value, ""), description,
+				)
+				if err != nil {
+					return nil, err
+				}
+				builder.AddRange(ipRange)
+			}
+		}
+		if ipConfig.Ipv6 != nil {
+			for _, pool := range ipConfig.Ipv6.PoolList {
+				if pool.StartIp == nil || pool.EndIp == nil {
+					continue
+				}
+				ipRange, err := parseIPRange(
+					ptr.Deref(pool.StartIp.Value, ""), ptr.Deref(pool.EndIp.Value, ""), description,
+				)
+				if err != nil {
+					return nil, err
+				}
+				builder.AddRange(ipRange)
+			}
+		}
+	}
+
+	ipSet, err := builder.IPSet()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build IP pools for %s: %w", description, err)
+	}
+	return ipSet, nil
+}
+
+func parseIPRange(start, end, description string) (netipx.IPRange, error) {
+	startAddr, err := netip.ParseAddr(start)
+	if err != nil {
+		return netipx.IPRange{}, fmt.Errorf("failed to parse IP pool start %q for %s: %w", start, description, err)
+	}
+	endAddr, err := netip.ParseAddr(end)
+	if err != nil {
+		return netipx.IPRange{}, fmt.Errorf("failed to parse IP pool end %q for %s: %w", end, description, err)
+	}
+	ipRange := netipx.IPRangeFrom(startAddr, endAddr)
+	if !ipRange.IsValid() {
+		return netipx.IPRange{}, fmt.Errorf("invalid IP pool range %s-%s for %s", start, end, description)
+	}
+	return ipRange, nil
 }
 
 func subnetPrefix(apiSubnet *networkingapi.Subnet, description string) (int32, error) {
@@ -0,0 +1,62 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"net/netip"
+
+	"github.com/google/uuid"
+	networkingapi "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/networking/v4/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("reservedIPAddress",
+	func(payload string, expected netip.Addr, expectedOK bool) {
+		var reservedIP networkingapi.ReservedIp
+		Expect(json.Unmarshal([]byte(payload), &reservedIP)).To(Succeed())
+
+		addr, ok, err := reservedIPAddress(&reservedIP)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(Equal(expectedOK))
+		Expect(addr).To(Equal(expected))
+	},
+	Entry("IPv4 address",
+		`{"ipv4Address": "10.0.0.10", "clientContext": "context"}`,
+		netip.MustParseAddr("10.0.0.10"), true),
+	Entry("IPv6 address",
+		`{"ipv6Address": "fd00::10", "clientContext": "context"}`,
+		netip.MustParseAddr("fd00::10"), true),
+	Entry("no address", `{"clientContext": "context"}`, netip.Addr{}, false),
+)
+
+var _ = DescribeTable("Subnet.HasIPConfigFor",
+	func(subnet *Subnet, addr netip.Addr, expected bool) {
+		Expect(subnet.HasIPConfigFor(addr)).To(Equal(expected))
+	},
+	Entry("IPv4 address of an IPv4 subnet",
+		NewSubnet(uuid.New(), 24, WithSubnetIPv4Prefix(24)), netip.IPv4Unspecified(), true),
+	Entry("IPv6 address of an IPv4 subnet",
+		NewSubnet(uuid.New(), 24, WithSubnetIPv4Prefix(24)), netip.IPv6Unspecified(), false),
+	Entry("IPv6 address of a dual-stack subnet",
+		NewSubnet(uuid.New(), 24, WithSubnetIPv4Prefix(24), WithSubnetIPv6Prefix(64)), netip.IPv6Unspecified(), true),
+	Entry("IPv6 address of a subnet without IP configuration",
+		NewSubnet(uuid.New(), 24), netip.IPv6Unspecified(), true),
+)
+
+var _ = DescribeTable("subnetLabel",
+	func(subnet *Subnet, subnetExtIDOrName, expected string) {
+		Expect(subnetLabel(subnet, subnetExtIDOrName)).To(Equal(expected))
+	},
+	Entry("subnet found by name",
+		NewSubnet(uuid.MustParse("4c9d9a3c-4f4e-4b6a-9a3b-6b1d6f2b0a11"), 24), "subnet",
+		"4c9d9a3c-4f4e-4b6a-9a3b-6b1d6f2b0a11"),
+	Entry("subnet found by extID",
+		NewSubnet(uuid.MustParse("4c9d9a3c-4f4e-4b6a-9a3b-6b1d6f2b0a11"), 24), "4C9D9A3C-4F4E-4B6A-9A3B-6B1D6F2B0A11",
+		"4c9d9a3c-4f4e-4b6a-9a3b-6b1d6f2b0a11"),
+	Entry("subnet not found by extID", nil, "{4C9D9A3C-4F4E-4B6A-9A3B-6B1D6F2B0A11}",
+		"4c9d9a3c-4f4e-4b6a-9a3b-6b1d6f2b0a11"),
+	Entry("subnet not found by name", nil, "subnet", ""),
+)
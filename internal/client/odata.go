@@ -0,0 +1,36 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"strings"
+
+	"github.com/nutanix-cloud-native/prism-go-client/converged"
+)
+
+// odataString returns the value as an OData string literal. The value is enclosed in single quotes, with any
+// single quote within the value escaped by doubling it, so that the value cannot end the literal early and change
+// the meaning of the filter.
+func odataString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// odataFilter builds an OData $filter expression out of comparisons joined with "and". Properties are expected to
+// be constant property names, while values are always escaped as string literals.
+type odataFilter []string
+
+// eq adds a comparison of the property with the string value.
+func (f odataFilter) eq(property, value string) odataFilter {
+	return append(f, property+" eq "+odataString(value))
+}
+
+// String returns the $filter expression.
+func (f odataFilter) String() string {
+	return strings.Join(f, " and ")
+}
+
+// option returns the filter as an option of a list request.
+func (f odataFilter) option() converged.ODataOption {
+	return converged.WithFilter(f.String())
+}
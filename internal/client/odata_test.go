@@ -0,0 +1,45 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("odataString",
+	func(value, expected string) {
+		Expect(odataString(value)).To(Equal(expected))
+	},
+	Entry("plain name", "subnet-1", `'subnet-1'`),
+	Entry("empty name", "", `''`),
+	Entry("apostrophe", "O'Brien's subnet", `'O''Brien''s subnet'`),
+	Entry("only quotes", "''", `''''''`),
+	Entry("injected comparison", "x' or name ne 'y", `'x'' or name ne ''y'`),
+	Entry("injected group", "a') or (true", `'a'') or (true'`),
+	Entry("trailing quote", "subnet'", `'subnet'''`),
+	Entry("backslashes", `sub\'net\`, `'sub\''net\'`),
+	Entry("percent-encoded quote", "sub%27net", `'sub%27net'`),
+	Entry("double quotes", `"subnet"`, `'"subnet"'`),
+	Entry("unicode", "sübnet-ネット", `'sübnet-ネット'`),
+)
+
+var _ = DescribeTable("odataFilter",
+	func(filter odataFilter, expected string) {
+		Expect(filter.String()).To(Equal(expected))
+	},
+	Entry("no comparisons", odataFilter{}, ""),
+	Entry("single comparison",
+		odataFilter{}.eq("name", "subnet-1"),
+		`name eq 'subnet-1'`),
+	Entry("multiple comparisons",
+		odataFilter{}.eq("name", "subnet-1").eq("clusterReference", "00000000-0000-0000-0000-000000000001"),
+		`name eq 'subnet-1' and clusterReference eq '00000000-0000-0000-0000-000000000001'`),
+	Entry("injected comparison stays within the value",
+		odataFilter{}.eq("name", "x' or name ne 'y").eq("clusterReference", "c"),
+		`name eq 'x'' or name ne ''y' and clusterReference eq 'c'`),
+	Entry("injected client context stays within the value",
+		odataFilter{}.eq("clientContext", "ns/claim') or (true"),
+		`clientContext eq 'ns/claim'') or (true'`),
+)
@@ -0,0 +1,139 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultResolutionCacheTTL is the default time subnets and clusters resolved from Prism Central are cached for.
+const DefaultResolutionCacheTTL = 5 * time.Minute
+
+var resolutions = newResolutionCache(DefaultResolutionCacheTTL)
+
+// SetResolutionCacheTTL sets the time subnets and clusters resolved from Prism Central are cached for. A TTL of
+// zero disables the cache.
+func SetResolutionCacheTTL(ttl time.Duration) {
+	resolutions.setTTL(ttl)
+}
+
+// InvalidateResolutionCache drops the subnets and clusters cached for the Prism Central at the given endpoint, in
+// host:port form, e.g. because the subnet or cluster names of a pool changed.
+func InvalidateResolutionCache(endpoint string) {
+	resolutions.invalidate(endpoint)
+}
+
+// resolutionKey identifies a subnet or cluster resolved by extID or name from a Prism Central.
+type resolutionKey struct {
+	// endpoint is the host:port of the Prism Central.
+	endpoint string
+	kind     resolutionKind
+	// cluster is the cluster a subnet name is resolved in, if any.
+	cluster string
+	// name is the extID or name the subnet or cluster is resolved by.
+	name string
+}
+
+type resolutionKind string
+
+const (
+	subnetResolution  resolutionKind = "subnet"
+	clusterResolution resolutionKind = "cluster"
+)
+
+type resolutionEntry struct {
+	value   any
+	expires time.Time
+}
+
+// resolutionCache caches subnets and clusters resolved from Prism Central for a TTL, so that resolving the same
+// subnet for every IP reservation does not hit Prism Central each time.
+type resolutionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[resolutionKey]resolutionEntry
+}
+
+func newResolutionCache(ttl time.Duration) *resolutionCache {
+	return &resolutionCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[resolutionKey]resolutionEntry{},
+	}
+}
+
+func (c *resolutionCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+	clear(c.entries)
+}
+
+func (c *resolutionCache) get(key resolutionKey) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *resolutionCache) set(key resolutionKey, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl <= 0 {
+		return
+	}
+	c.entries[key] = resolutionEntry{value: value, expires: c.now().Add(c.ttl)}
+}
+
+func (c *resolutionCache) delete(key resolutionKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// invalidate drops all entries of the Prism Central at the endpoint.
+func (c *resolutionCache) invalidate(endpoint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.endpoint == endpoint {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// invalidateSubnet drops all entries of the Prism Central at the endpoint that resolved to the subnet extID, e.g.
+// because the subnet was deleted.
+func (c *resolutionCache) invalidateSubnet(endpoint string, extID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		subnet, ok := entry.value.(*Subnet)
+		if key.endpoint == endpoint && ok && subnet.ExtID() == extID {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// getCached returns the value cached for the key if it is of type T.
+func getCached[T any](c *resolutionCache, key resolutionKey) (T, bool) {
+	value, ok := c.get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	typed, ok := value.(T)
+	return typed, ok
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("resolutionCache", func() {
+	const endpoint = "prism.example.com:9440"
+
+	var (
+		cache *resolutionCache
+		now   time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		cache = newResolutionCache(time.Minute)
+		cache.now = func() time.Time {
+			return now
+		}
+	})
+
+	subnetKey := func(endpoint, name string) resolutionKey {
+		return resolutionKey{endpoint: endpoint, kind: subnetResolution, cluster: "test-cluster", name: name}
+	}
+
+	It("should return cached values until the TTL expires", func() {
+		subnet := NewSubnet(uuid.New(), 24)
+		cache.set(subnetKey(endpoint, "test-subnet"), subnet)
+
+		cached, ok := getCached[*Subnet](cache, subnetKey(endpoint, "test-subnet"))
+		Expect(ok).To(BeTrue())
+		Expect(cached).To(BeIdenticalTo(subnet))
+		_, ok = getCached[*Cluster](cache, subnetKey(endpoint, "test-subnet"))
+		Expect(ok).To(BeFalse())
+
+		now = now.Add(time.Minute)
+		_, ok = getCached[*Subnet](cache, subnetKey(endpoint, "test-subnet"))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not cache values if the TTL is zero", func() {
+		cache.setTTL(0)
+		cache.set(subnetKey(endpoint, "test-subnet"), NewSubnet(uuid.New(), 24))
+
+		_, ok := getCached[*Subnet](cache, subnetKey(endpoint, "test-subnet"))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should only invalidate the values of the endpoint", func() {
+		cache.set(subnetKey(endpoint, "test-subnet"), NewSubnet(uuid.New(), 24))
+		cache.set(subnetKey("other.example.com:9440", "test-subnet"), NewSubnet(uuid.New(), 24))
+
+		cache.invalidate(endpoint)
+
+		_, ok := getCached[*Subnet](cache, subnetKey(endpoint, "test-subnet"))
+		Expect(ok).To(BeFalse())
+		_, ok = getCached[*Subnet](cache, subnetKey("other.example.com:9440", "test-subnet"))
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should invalidate all names resolved to a subnet", func() {
+		subnet := NewSubnet(uuid.New(), 24)
+		cache.set(subnetKey(endpoint, "test-subnet"), subnet)
+		cache.set(subnetKey(endpoint, subnet.ExtID().String()), subnet)
+		cache.set(subnetKey(endpoint, "other-subnet"), NewSubnet(uuid.New(), 24))
+
+		cache.invalidateSubnet(endpoint, subnet.ExtID())
+
+		_, ok := getCached[*Subnet](cache, subnetKey(endpoint, "test-subnet"))
+		Expect(ok).To(BeFalse())
+		_, ok = getCached[*Subnet](cache, subnetKey(endpoint, subnet.ExtID().String()))
+		Expect(ok).To(BeFalse())
+		_, ok = getCached[*Subnet](cache, subnetKey(endpoint, "other-subnet"))
+		Expect(ok).To(BeTrue())
+	})
+})
@@ -0,0 +1,160 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package clientcontext renders the client contexts that IPs are reserved with in Prism Central from a template, and
+// parses them back to the claim they were reserved for.
+package clientcontext
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// DefaultTemplate is the template used if a pool does not set a client context template. It renders the UID of the
+// claim, which is the client context used before client context templates were introduced.
+const DefaultTemplate = "{{ .UID }}"
+
+// Fields are the fields of a claim that a client context template can reference.
+type Fields struct {
+	// ClusterName is the name of the Cluster the claim belongs to. It is empty if the claim does not belong to a
+	// Cluster.
+	ClusterName string
+	// Namespace is the namespace of the claim.
+	Namespace string
+	// Name is the name of the claim.
+	Name string
+	// UID is the UID of the claim.
+	UID string
+}
+
+// fieldPatterns are the patterns matching the values of each field when parsing a client context.
+var fieldPatterns = map[string]string{
+	"ClusterName": `[-a-z0-9.]*`,
+	"Namespace":   `[-a-z0-9.]+`,
+	"Name":        `[-a-z0-9.]+`,
+	"UID":         `[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`,
+}
+
+// Template renders client contexts from the fields of a claim, and parses them back.
+type Template struct {
+	template *template.Template
+	pattern  *regexp.Regexp
+	// fields are the fields matched by the capturing groups of pattern, in order.
+	fields []string
+}
+
+// Parse parses a client context template. The template is a Go template that may only consist of text and of the
+// fields of Fields, e.g. "{{ .ClusterName }}/{{ .Namespace }}/{{ .Name }}/{{ .UID }}". It must reference the UID,
+// so that each client context maps to a single claim. An empty text parses the DefaultTemplate.
+func Parse(text string) (*Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+
+	tmpl, err := template.New("clientContext").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse client context template: %w", err)
+	}
+
+	var pattern strings.Builder
+	var fields []string
+	pattern.WriteString("^")
+	for _, node := range tmpl.Root.Nodes {
+		switch node := node.(type) {
+		case *parse.TextNode:
+			pattern.WriteString(regexp.QuoteMeta(string(node.Text)))
+		case *parse.ActionNode:
+			field, ok := actionField(node)
+			if !ok {
+				return nil, fmt.Errorf(
+					"unsupported action %s in client context template: only the fields %s are supported",
+					node, strings.Join(supportedFields(), ", "),
+				)
+			}
+			pattern.WriteString("(" + fieldPatterns[field] + ")")
+			fields = append(fields, field)
+		default:
+			return nil, fmt.Errorf(
+				"unsupported node %s in client context template: only the fields %s are supported",
+				node, strings.Join(supportedFields(), ", "),
+			)
+		}
+	}
+	pattern.WriteString("$")
+
+	if !slices.Contains(fields, "UID") {
+		return nil, errors.New("client context template must reference {{ .UID }}")
+	}
+
+	return &Template{
+		template: tmpl,
+		pattern:  regexp.MustCompile(pattern.String()),
+		fields:   fields,
+	}, nil
+}
+
+// actionField returns the field of Fields that the action renders, if the action only renders a field.
+func actionField(node *parse.ActionNode) (string, bool) {
+	if len(node.Pipe.Decl) > 0 || len(node.Pipe.Cmds) != 1 || len(node.Pipe.Cmds[0].Args) != 1 {
+		return "", false
+	}
+	field, ok := node.Pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok || len(field.Ident) != 1 {
+		return "", false
+	}
+	if _, ok := fieldPatterns[field.Ident[0]]; !ok {
+		return "", false
+	}
+	return field.Ident[0], true
+}
+
+func supportedFields() []string {
+	fields := make([]string, 0, len(fieldPatterns))
+	for field := range fieldPatterns {
+		fields = append(fields, "{{ ."+field+" }}")
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// Execute renders the client context of a claim.
+func (t *Template) Execute(fields Fields) (string, error) {
+	var clientContext strings.Builder
+	if err := t.template.Execute(&clientContext, fields); err != nil {
+		return "", fmt.Errorf("failed to render client context: %w", err)
+	}
+	return clientContext.String(), nil
+}
+
+// ParseClientContext parses a client context rendered by the template back to the fields of the claim it was
+// rendered for. It returns false if the client context was not rendered by the template. The UID is always
+// recovered, while the other fields may be split ambiguously if the template does not separate them with characters
+// that are invalid in Kubernetes names, e.g. "/".
+func (t *Template) ParseClientContext(clientContext string) (Fields, bool) {
+	matches := t.pattern.FindStringSubmatch(clientContext)
+	if matches == nil {
+		return Fields{}, false
+	}
+
+	values := make(map[string]string, len(t.fields))
+	for i, field := range t.fields {
+		value := matches[i+1]
+		if previous, ok := values[field]; ok && previous != value {
+			return Fields{}, false
+		}
+		values[field] = value
+	}
+
+	var fields Fields
+	v := reflect.ValueOf(&fields).Elem()
+	for field, value := range values {
+		v.FieldByName(field).SetString(value)
+	}
+	return fields, true
+}
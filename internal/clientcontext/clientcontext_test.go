@@ -0,0 +1,92 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package clientcontext
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const uid = "5d9c7a4e-3f0b-4c1e-9a61-2b8f0d7e4c13"
+
+var _ = DescribeTable("rendering and parsing back a client context",
+	func(text string, fields Fields, expected string) {
+		tmpl, err := Parse(text)
+		Expect(err).NotTo(HaveOccurred())
+
+		clientContext, err := tmpl.Execute(fields)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clientContext).To(Equal(expected))
+
+		parsed, ok := tmpl.ParseClientContext(clientContext)
+		Expect(ok).To(BeTrue())
+		Expect(parsed.UID).To(Equal(fields.UID))
+	},
+	Entry("default template", "",
+		Fields{ClusterName: "cluster", Namespace: "default", Name: "claim", UID: uid},
+		uid),
+	Entry("all fields",
+		"{{ .ClusterName }}/{{ .Namespace }}/{{ .Name }}/{{ .UID }}",
+		Fields{ClusterName: "cluster", Namespace: "default", Name: "claim-0", UID: uid},
+		"cluster/default/claim-0/"+uid),
+	Entry("claim without cluster",
+		"{{ .ClusterName }}/{{ .Namespace }}/{{ .Name }}/{{ .UID }}",
+		Fields{Namespace: "default", Name: "claim", UID: uid},
+		"/default/claim/"+uid),
+	Entry("text with regular expression metacharacters",
+		"caipamx:{{.Name}} ({{.UID}})",
+		Fields{Name: "claim.v1", UID: uid},
+		"caipamx:claim.v1 ("+uid+")"),
+	Entry("repeated field", "{{ .Name }}-{{ .UID }}-{{ .Name }}",
+		Fields{Name: "claim", UID: uid},
+		"claim-"+uid+"-claim"),
+)
+
+var _ = Describe("ParseClientContext", func() {
+	It("should recover all fields separated by characters invalid in names", func() {
+		tmpl, err := Parse("{{ .ClusterName }}/{{ .Namespace }}/{{ .Name }}/{{ .UID }}")
+		Expect(err).NotTo(HaveOccurred())
+		fields, ok := tmpl.ParseClientContext("my-cluster/kube-system/cp-endpoint/" + uid)
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(Equal(Fields{
+			ClusterName: "my-cluster",
+			Namespace:   "kube-system",
+			Name:        "cp-endpoint",
+			UID:         uid,
+		}))
+	})
+
+	DescribeTable("should not parse client contexts that were not rendered by the template",
+		func(clientContext string) {
+			tmpl, err := Parse("{{ .Namespace }}/{{ .Name }}/{{ .UID }}")
+			Expect(err).NotTo(HaveOccurred())
+			_, ok := tmpl.ParseClientContext(clientContext)
+			Expect(ok).To(BeFalse())
+		},
+		Entry("bare UID", uid),
+		Entry("invalid UID", "default/claim/not-a-uid"),
+		Entry("trailing text", "default/claim/"+uid+"/extra"),
+		Entry("missing field", "default/"+uid),
+	)
+
+	It("should not parse a client context whose repeated field differs", func() {
+		tmpl, err := Parse("{{ .Name }}/{{ .UID }}/{{ .Name }}")
+		Expect(err).NotTo(HaveOccurred())
+		_, ok := tmpl.ParseClientContext("a/" + uid + "/b")
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = DescribeTable("Parse errors",
+	func(text string) {
+		Expect(Parse(text)).Error().To(HaveOccurred())
+	},
+	Entry("invalid syntax", "{{ .UID "),
+	Entry("missing UID", "{{ .Namespace }}/{{ .Name }}"),
+	Entry("unknown field", "{{ .Pool }}/{{ .UID }}"),
+	Entry("function call", "{{ printf \"%s\" .UID }}"),
+	Entry("conditional", "{{ if .ClusterName }}{{ .ClusterName }}/{{ end }}{{ .UID }}"),
+	Entry("variable", "{{ $uid := .UID }}{{ $uid }}"),
+	Entry("nested field", "{{ .UID.Value }}"),
+)
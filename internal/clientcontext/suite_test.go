@@ -0,0 +1,16 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package clientcontext
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestClientContext(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Client Context Suite")
+}
@@ -0,0 +1,372 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go4.org/netipx"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
+	ipamv1 "sigs.k8s.io/cluster-api/api/ipam/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/nutanix-cloud-native/prism-go-client/environment/credentials"
+
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/test/fakepc"
+)
+
+const (
+	poolName   = "test-pool"
+	secretName = "test-secret"
+)
+
+func credentialsData(username, password string) map[string]string {
+	return map[string]string{
+		credentials.KeyName: fmt.Sprintf(
+			`[{"type": "basic_auth", "data": {"prismCentral": {"username": %q, "password": %q}}}]`,
+			username,
+			password,
+		),
+	}
+}
+
+func newClaim(name, namespace string) *ipamv1.IPAddressClaim {
+	return &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: ipamv1.IPAddressClaimSpec{
+			PoolRef: ipamv1.IPPoolReference{
+				APIGroup: v1alpha1.GroupVersion.Group,
+				Kind:     v1alpha1.NutanixIPPoolKind,
+				Name:     poolName,
+			},
+		},
+	}
+}
+
+var _ = Describe("IPAddressClaim lifecycle against a fake Prism Central", func() {
+	var (
+		namespace   string
+		subnetExtID uuid.UUID
+		pool        *v1alpha1.NutanixIPPool
+		secret      *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		ns, err := env.CreateNamespace(context.Background(), "integration")
+		Expect(err).NotTo(HaveOccurred())
+		namespace = ns.Name
+
+		subnetExtID = prismCentral.AddSubnet(fakepc.Subnet{
+			Name:    "subnet-" + namespace,
+			Prefix:  netip.MustParsePrefix("10.0.0.0/24"),
+			Gateway: netip.MustParseAddr("10.0.0.1"),
+			Pools:   []netipx.IPRange{netipx.MustParseIPRange("10.0.0.10-10.0.0.19")},
+		})
+
+		username, password := prismCentral.Credentials()
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: namespace,
+			},
+			StringData: credentialsData(username, password),
+		}
+		Expect(env.CreateAndWait(context.Background(), secret)).To(Succeed())
+
+		pool = &v1alpha1.NutanixIPPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      poolName,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.NutanixIPPoolSpec{
+				PrismCentral: v1alpha1.PrismCentral{
+					Address: prismCentral.Host(),
+					Port:    uint16(prismCentral.Port()), //nolint:gosec // Ports of local listeners fit in uint16.
+					CredentialsSecretRef: v1alpha1.LocalSecretRef{
+						Name: secretName,
+					},
+					Insecure: true,
+				},
+				Subnet: subnetExtID.String(),
+			},
+		}
+	})
+
+	createPool := func() {
+		GinkgoHelper()
+		Expect(env.CreateAndWait(context.Background(), pool)).To(Succeed())
+		DeferCleanup(env.CleanupAndWait, context.Background(), pool, secret)
+	}
+
+	createClaim := func(name string) *ipamv1.IPAddressClaim {
+		GinkgoHelper()
+		claim := newClaim(name, namespace)
+		Expect(env.Create(context.Background(), claim)).To(Succeed())
+		DeferCleanup(env.CleanupAndWait, context.Background(), claim)
+		return claim
+	}
+
+	addressFor := func(g Gomega, claim *ipamv1.IPAddressClaim) *ipamv1.IPAddress {
+		address := &ipamv1.IPAddress{}
+		g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(claim), address)).To(Succeed())
+		return address
+	}
+
+	expectNoAddresses := func() {
+		GinkgoHelper()
+		Consistently(func(g Gomega) {
+			addresses := &ipamv1.IPAddressList{}
+			g.Expect(env.List(context.Background(), addresses, client.InNamespace(namespace))).To(Succeed())
+			g.Expect(addresses.Items).To(BeEmpty())
+			g.Expect(prismCentral.ReservedIPs(subnetExtID)).To(BeEmpty())
+		}).WithTimeout(2 * time.Second).Should(Succeed())
+	}
+
+	It("should reserve an IP for a new claim and release it when the claim is deleted", func() {
+		createPool()
+		claim := createClaim("claim")
+
+		Eventually(func(g Gomega) {
+			address := addressFor(g, claim)
+			g.Expect(address.Spec.Address).To(Equal("10.0.0.10"))
+			g.Expect(address.Spec.Prefix).To(HaveValue(BeEquivalentTo(24)))
+			g.Expect(address.Spec.Gateway).To(Equal("10.0.0.1"))
+			g.Expect(address.Annotations).To(HaveKeyWithValue(v1alpha1.SubnetAnnotation, subnetExtID.String()))
+
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(claim), claim)).To(Succeed())
+			g.Expect(claim.Status.AddressRef.Name).To(Equal(address.Name))
+			g.Expect(conditions.IsTrue(claim, ipamv1.IPAddressClaimReadyCondition)).To(BeTrue())
+		}).Should(Succeed())
+		Expect(prismCentral.ReservedIPs(subnetExtID)).To(Equal(map[netip.Addr]string{
+			netip.MustParseAddr("10.0.0.10"): string(claim.UID),
+		}))
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(pool), pool)).To(Succeed())
+			g.Expect(conditions.IsTrue(pool, v1alpha1.NutanixIPPoolReadyCondition)).To(BeTrue())
+			g.Expect(pool.Status.Addresses).NotTo(BeNil())
+			g.Expect(pool.Status.Addresses.Total).To(BeEquivalentTo(10))
+			g.Expect(pool.Status.Addresses.Free).To(BeEquivalentTo(9))
+		}).Should(Succeed())
+
+		Expect(env.Delete(context.Background(), claim)).To(Succeed())
+		Eventually(func(g Gomega) {
+			err := env.Get(context.Background(), client.ObjectKeyFromObject(claim), &ipamv1.IPAddress{})
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			err = env.Get(context.Background(), client.ObjectKeyFromObject(claim), &ipamv1.IPAddressClaim{})
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}).Should(Succeed())
+		Expect(prismCentral.ReservedIPs(subnetExtID)).To(BeEmpty())
+	})
+
+	It("should not reserve IPs while the pool is paused and reserve them once it is unpaused", func() {
+		pool.Annotations = map[string]string{clusterv1.PausedAnnotation: "true"}
+		createPool()
+		claim := createClaim("claim")
+
+		expectNoAddresses()
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(pool), pool)).To(Succeed())
+			delete(pool.Annotations, clusterv1.PausedAnnotation)
+			g.Expect(env.Update(context.Background(), pool)).To(Succeed())
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(addressFor(g, claim).Spec.Address).To(Equal("10.0.0.10"))
+		}).Should(Succeed())
+		Expect(prismCentral.ReservedIPs(subnetExtID)).To(HaveLen(1))
+	})
+
+	It("should reserve IPs with the new credentials once the credentials secret is rotated", func() {
+		createPool()
+		first := createClaim("first")
+		Eventually(func(g Gomega) {
+			g.Expect(addressFor(g, first).Spec.Address).To(Equal("10.0.0.10"))
+		}).Should(Succeed())
+
+		// The rotated password is kept for the following tests, as the claims of this test are released with it.
+		username, _ := prismCentral.Credentials()
+		prismCentral.SetCredentials(username, "rotated")
+		logins := prismCentral.Logins()
+
+		// IPs cannot be reserved until the secret is updated with the rotated password.
+		second := createClaim("second")
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(second), second)).To(Succeed())
+			g.Expect(conditions.IsFalse(second, ipamv1.IPAddressClaimReadyCondition)).To(BeTrue())
+		}).Should(Succeed())
+		Expect(prismCentral.ReservedIPs(subnetExtID)).To(HaveLen(1))
+
+		Expect(env.Get(context.Background(), client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		secret.StringData = credentialsData(username, "rotated")
+		Expect(env.Update(context.Background(), secret)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(addressFor(g, second).Spec.Address).To(Equal("10.0.0.11"))
+		}).Should(Succeed())
+		Expect(prismCentral.Logins()).To(BeNumerically(">", logins))
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(pool), pool)).To(Succeed())
+			g.Expect(conditions.IsTrue(pool, v1alpha1.NutanixIPPoolCredentialsValidCondition)).To(BeTrue())
+		}).Should(Succeed())
+	})
+
+	It("should release the IP of a claim recreated with another UID by clusterctl move", func() {
+		createPool()
+		claim := createClaim("claim")
+
+		address := &ipamv1.IPAddress{}
+		Eventually(func(g Gomega) {
+			address = addressFor(g, claim)
+			g.Expect(address.Spec.Address).To(Equal("10.0.0.10"))
+			g.Expect(address.Annotations).To(HaveKeyWithValue(v1alpha1.ClientContextAnnotation, string(claim.UID)))
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(claim), claim)).To(Succeed())
+			g.Expect(claim.Annotations).To(HaveKeyWithValue(v1alpha1.ClientContextAnnotation, string(claim.UID)))
+		}).Should(Succeed())
+		clientContext := string(claim.UID)
+
+		// Recreate the claim and its IPAddress the way clusterctl move does: the claim is paused, and the objects
+		// are deleted without being finalized and created again with the same metadata.
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(claim), claim)).To(Succeed())
+			claim.Annotations[clusterv1.PausedAnnotation] = ""
+			g.Expect(env.Update(context.Background(), claim)).To(Succeed())
+		}).Should(Succeed())
+		movedClaim := claim.DeepCopy()
+		movedAddress := address.DeepCopy()
+		for _, obj := range []client.Object{address, claim} {
+			Eventually(func(g Gomega) {
+				g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+				obj.SetFinalizers(nil)
+				g.Expect(env.Update(context.Background(), obj)).To(Succeed())
+			}).Should(Succeed())
+			Expect(env.CleanupAndWait(context.Background(), obj)).To(Succeed())
+		}
+
+		for _, obj := range []client.Object{movedClaim, movedAddress} {
+			obj.SetResourceVersion("")
+			obj.SetUID("")
+		}
+		Expect(env.Create(context.Background(), movedClaim)).To(Succeed())
+		Expect(movedClaim.UID).NotTo(BeEquivalentTo(clientContext))
+		movedAddress.OwnerReferences[0].UID = movedClaim.UID
+		Expect(env.Create(context.Background(), movedAddress)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(movedClaim), movedClaim)).To(Succeed())
+			delete(movedClaim.Annotations, clusterv1.PausedAnnotation)
+			g.Expect(env.Update(context.Background(), movedClaim)).To(Succeed())
+		}).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(movedClaim), movedClaim)).To(Succeed())
+			g.Expect(movedClaim.Status.AddressRef.Name).To(Equal(movedAddress.Name))
+			g.Expect(conditions.IsTrue(movedClaim, ipamv1.IPAddressClaimReadyCondition)).To(BeTrue())
+		}).Should(Succeed())
+		Expect(movedClaim.Annotations).To(HaveKeyWithValue(v1alpha1.ClientContextAnnotation, clientContext))
+		Expect(prismCentral.ReservedIPs(subnetExtID)).To(Equal(map[netip.Addr]string{
+			netip.MustParseAddr("10.0.0.10"): clientContext,
+		}))
+
+		Expect(env.CleanupAndWait(context.Background(), movedClaim)).To(Succeed())
+		Expect(prismCentral.ReservedIPs(subnetExtID)).To(BeEmpty())
+	})
+
+	It("should skip the IPs reserved by others across all pages of reserved IPs", func() {
+		subnetExtID = prismCentral.AddSubnet(fakepc.Subnet{
+			Name:    "large-subnet-" + namespace,
+			Prefix:  netip.MustParsePrefix("10.0.0.0/24"),
+			Gateway: netip.MustParseAddr("10.0.0.1"),
+			Pools:   []netipx.IPRange{netipx.MustParseIPRange("10.0.0.10-10.0.0.250")},
+		})
+		// Reserve more IPs than fit in a single page of reserved IPs, so that the IP picked for the claim is only
+		// free if all pages were listed.
+		addrs := make([]netip.Addr, 0, 150)
+		for addr := netip.MustParseAddr("10.0.0.10"); len(addrs) < cap(addrs); addr = addr.Next() {
+			addrs = append(addrs, addr)
+		}
+		Expect(prismCentral.ReserveIPs(subnetExtID, "someone-else", addrs...)).To(Succeed())
+		pool.Spec.Subnet = subnetExtID.String()
+		pool.Spec.AllowedRanges = []string{"10.0.0.0/24"}
+		createPool()
+		claim := createClaim("claim")
+
+		Eventually(func(g Gomega) {
+			g.Expect(addressFor(g, claim).Spec.Address).To(Equal("10.0.0.160"))
+		}).Should(Succeed())
+		Expect(prismCentral.ReservedIPs(subnetExtID)).To(
+			HaveKeyWithValue(netip.MustParseAddr("10.0.0.160"), string(claim.UID)),
+		)
+	})
+
+	It("should reserve a distinct IP for each of many concurrent claims", func() {
+		createPool()
+
+		const count = 10
+		claims := make([]*ipamv1.IPAddressClaim, count)
+		errs := make([]error, count)
+		var wg sync.WaitGroup
+		for i := range count {
+			claims[i] = newClaim(fmt.Sprintf("claim-%d", i), namespace)
+			wg.Go(func() {
+				errs[i] = env.Create(context.Background(), claims[i])
+			})
+		}
+		wg.Wait()
+		for i := range count {
+			Expect(errs[i]).NotTo(HaveOccurred())
+			DeferCleanup(env.CleanupAndWait, context.Background(), claims[i])
+		}
+
+		expected := map[netip.Addr]string{}
+		Eventually(func(g Gomega) {
+			clear(expected)
+			for _, claim := range claims {
+				addr, err := netip.ParseAddr(addressFor(g, claim).Spec.Address)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(expected).NotTo(HaveKey(addr))
+				expected[addr] = string(claim.UID)
+			}
+		}).WithTimeout(30 * time.Second).Should(Succeed())
+		Expect(prismCentral.ReservedIPs(subnetExtID)).To(Equal(expected))
+
+		for _, claim := range claims {
+			Expect(env.Delete(context.Background(), claim)).To(Succeed())
+		}
+		Eventually(func(g Gomega) {
+			g.Expect(prismCentral.ReservedIPs(subnetExtID)).To(BeEmpty())
+		}).WithTimeout(30 * time.Second).Should(Succeed())
+	})
+
+	It("should fail the claim once the subnet is exhausted", func() {
+		addrs := make([]netip.Addr, 0, 10)
+		for addr := netip.MustParseAddr("10.0.0.10"); len(addrs) < 10; addr = addr.Next() {
+			addrs = append(addrs, addr)
+		}
+		Expect(prismCentral.ReserveIPs(subnetExtID, "someone-else", addrs...)).To(Succeed())
+		createPool()
+		claim := createClaim("claim")
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(claim), claim)).To(Succeed())
+			g.Expect(conditions.IsFalse(claim, ipamv1.IPAddressClaimReadyCondition)).To(BeTrue())
+		}).Should(Succeed())
+		Expect(prismCentral.ReservedIPs(subnetExtID)).To(HaveLen(10))
+	})
+})
@@ -0,0 +1,99 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package integration runs the controllers with the real Prism Central client against a fake Prism Central.
+package integration
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	clientgocache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/cluster-api-ipam-provider-in-cluster/pkg/ipamutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/controllers"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/index"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/test/envtest"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/test/fakepc"
+)
+
+var (
+	ctx          = ctrl.SetupSignalHandler()
+	env          *envtest.Environment
+	prismCentral *fakepc.Server
+	// secretInformer is shared with the controllers, so that tests can wait for it to observe secret updates.
+	secretInformer coreinformers.SecretInformer
+)
+
+func TestIntegration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Integration Suite")
+}
+
+func TestMain(m *testing.M) {
+	RegisterFailHandler(Fail)
+
+	prismCentral = fakepc.NewServer()
+
+	setupReconcilers := func(ctx context.Context, mgr ctrl.Manager) {
+		clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+		Expect(err).NotTo(HaveOccurred())
+		informerFactory := informers.NewSharedInformerFactory(clientset, time.Minute)
+		secretInformer = informerFactory.Core().V1().Secrets()
+		cmInformer := informerFactory.Core().V1().ConfigMaps()
+		for _, informer := range []clientgocache.SharedIndexInformer{secretInformer.Informer(), cmInformer.Informer()} {
+			go informer.Run(ctx.Done())
+			Expect(clientgocache.WaitForCacheSync(ctx.Done(), informer.HasSynced)).To(BeTrue())
+		}
+
+		// Requeue quickly so that tests do not wait for the default backoff.
+		opts := controllers.DefaultReconcilerOptions()
+		flags := pflag.NewFlagSet("integration", pflag.ContinueOnError)
+		opts.AddFlags(flags)
+		Expect(flags.Parse([]string{
+			"--min-requeue-delay=100ms",
+			"--max-requeue-delay=1s",
+			"--pool-sync-period=1s",
+		})).To(Succeed())
+
+		Expect(index.SetupIndexes(ctx, mgr)).To(Succeed())
+		Expect(
+			(&ipamutil.ClaimReconciler{
+				Client: mgr.GetClient(),
+				Scheme: mgr.GetScheme(),
+				Adapter: controllers.NewNutanixProviderAdapter(
+					mgr.GetClient(),
+					"",
+					secretInformer,
+					cmInformer,
+					mgr.GetEventRecorder("caipamx-ipaddressclaim"),
+					opts,
+				),
+			}).SetupWithManager(ctx, mgr),
+		).To(Succeed())
+		Expect(
+			controllers.NewNutanixIPPoolReconciler(mgr.GetClient(), "", secretInformer, cmInformer, opts).
+				SetupWithManager(ctx, mgr),
+		).To(Succeed())
+	}
+	SetDefaultEventuallyPollingInterval(100 * time.Millisecond)
+	SetDefaultEventuallyTimeout(10 * time.Second)
+	code := envtest.Run(ctx, envtest.RunInput{
+		M: m,
+		SetupEnv: func(e *envtest.Environment) {
+			env = e
+		},
+		SetupReconcilers: setupReconcilers,
+	})
+	prismCentral.Close()
+	os.Exit(code)
+}
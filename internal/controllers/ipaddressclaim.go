@@ -6,22 +6,28 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
 	"github.com/spf13/pflag"
+	"go4.org/netipx"
 	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-ipam-provider-in-cluster/pkg/ipamutil"
 	ipampredicates "sigs.k8s.io/cluster-api-ipam-provider-in-cluster/pkg/predicates"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	ipamv1 "sigs.k8s.io/cluster-api/api/ipam/v1beta2"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,16 +38,44 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/nutanix-cloud-native/prism-go-client/environment/credentials"
-
 	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
 	pcclient "github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/client"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/clientcontext"
 	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/index"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/poolutil"
+)
+
+const (
+	// IPReservedReason is the reason of the event emitted when an IP is reserved for a claim.
+	IPReservedReason = "IPReserved"
+
+	// IPReservationAdoptedReason is the reason of the event emitted when an IP already reserved for a claim is
+	// adopted instead of reserving another.
+	IPReservationAdoptedReason = "IPReservationAdopted"
+
+	// IPReservationFailedReason is the reason of the event emitted when an IP could not be reserved for a claim.
+	IPReservationFailedReason = "IPReservationFailed"
+
+	// IPReleasedReason is the reason of the event emitted when the IP of a claim is released.
+	IPReleasedReason = "IPReleased"
+
+	// IPReleaseFailedReason is the reason of the event emitted when the IP of a claim could not be released.
+	IPReleaseFailedReason = "IPReleaseFailed"
+
+	// SubnetResolutionFailedReason is the reason of the event emitted when the subnet to allocate a claim's IP
+	// from could not be resolved.
+	SubnetResolutionFailedReason = "SubnetResolutionFailed"
+
+	// CredentialsInvalidReason is the reason of the event emitted when no Prism Central client could be created
+	// from the credentials referenced by the pool.
+	CredentialsInvalidReason = "CredentialsInvalid"
 )
 
 type genericNutanixIPPool interface {
 	ctrlclient.Object
 	PoolSpec() *v1alpha1.NutanixIPPoolSpec
+	PoolStatus() *v1alpha1.NutanixIPPoolStatus
+	conditions.Setter
 }
 
 // NutanixProviderAdapter is used as middle layer for provider integration.
@@ -51,6 +85,7 @@ type NutanixProviderAdapter struct {
 	pcClientGetter   func(pcclient.CachedClientParams) (pcclient.Client, error)
 	secretInformer   coreinformers.SecretInformer
 	cmInformer       coreinformers.ConfigMapInformer
+	recorder         events.EventRecorder
 	opts             reconcilerOptions
 }
 
@@ -59,6 +94,22 @@ var _ ipamutil.ProviderAdapter = &NutanixProviderAdapter{}
 type reconcilerOptions struct {
 	maxConcurrentReconciles        int
 	minRequeueTime, maxRequeueTime time.Duration
+	poolSyncPeriod                 time.Duration
+
+	reservationGCEnabled     bool
+	reservationGCInterval    time.Duration
+	reservationGCGracePeriod time.Duration
+	reservationGCDryRun      bool
+	// reservationGCClientContextPrefix is the prefix of the client contexts of the reservations owned by this
+	// management cluster, which are the only ones released by the reservation garbage collector.
+	reservationGCClientContextPrefix string
+
+	prismCentralQPS         float64
+	prismCentralBurst       int
+	prismCentralMaxInFlight int
+
+	prismCentralCircuitBreakerThreshold int
+	prismCentralCircuitBreakerCoolOff   time.Duration
 }
 
 func DefaultReconcilerOptions() reconcilerOptions {
@@ -66,6 +117,34 @@ func DefaultReconcilerOptions() reconcilerOptions {
 		maxConcurrentReconciles: 10,
 		minRequeueTime:          500 * time.Millisecond,
 		maxRequeueTime:          1 * time.Minute,
+		poolSyncPeriod:          5 * time.Minute,
+
+		reservationGCInterval:    1 * time.Hour,
+		reservationGCGracePeriod: 1 * time.Hour,
+
+		prismCentralQPS:         pcclient.DefaultLimits.QPS,
+		prismCentralBurst:       pcclient.DefaultLimits.Burst,
+		prismCentralMaxInFlight: pcclient.DefaultLimits.MaxInFlight,
+
+		prismCentralCircuitBreakerThreshold: pcclient.DefaultCircuitBreakerSettings.FailureThreshold,
+		prismCentralCircuitBreakerCoolOff:   pcclient.DefaultCircuitBreakerSettings.CoolOff,
+	}
+}
+
+// PrismCentralLimits returns the limits on the requests sent to each Prism Central.
+func (o *reconcilerOptions) PrismCentralLimits() pcclient.Limits {
+	return pcclient.Limits{
+		QPS:         o.prismCentralQPS,
+		Burst:       o.prismCentralBurst,
+		MaxInFlight: o.prismCentralMaxInFlight,
+	}
+}
+
+// PrismCentralCircuitBreaker returns the settings of the circuit breaker of each Prism Central.
+func (o *reconcilerOptions) PrismCentralCircuitBreaker() pcclient.CircuitBreakerSettings {
+	return pcclient.CircuitBreakerSettings{
+		FailureThreshold: o.prismCentralCircuitBreakerThreshold,
+		CoolOff:          o.prismCentralCircuitBreakerCoolOff,
 	}
 }
 
@@ -88,6 +167,76 @@ func (o *reconcilerOptions) AddFlags(fs *pflag.FlagSet) {
 		o.maxRequeueTime,
 		"Maximum time to wait when requeueing on error",
 	)
+	fs.DurationVar(
+		&o.poolSyncPeriod,
+		"pool-sync-period",
+		o.poolSyncPeriod,
+		"Interval at which pool status is refreshed from Prism Central",
+	)
+	fs.BoolVar(
+		&o.reservationGCEnabled,
+		"reservation-gc-enabled",
+		o.reservationGCEnabled,
+		"Release IPs reserved in pool subnets for IPAddressClaims that no longer exist. "+
+			"Must not be enabled when only watching a single namespace",
+	)
+	fs.DurationVar(
+		&o.reservationGCInterval,
+		"reservation-gc-interval",
+		o.reservationGCInterval,
+		"Interval at which pool subnets are checked for orphaned reservations",
+	)
+	fs.DurationVar(
+		&o.reservationGCGracePeriod,
+		"reservation-gc-grace-period",
+		o.reservationGCGracePeriod,
+		"Minimum time a reservation must be orphaned for before it is released",
+	)
+	fs.BoolVar(
+		&o.reservationGCDryRun,
+		"reservation-gc-dry-run",
+		o.reservationGCDryRun,
+		"Only report orphaned reservations via logs and events instead of releasing them",
+	)
+	fs.StringVar(
+		&o.reservationGCClientContextPrefix,
+		"reservation-gc-client-context-prefix",
+		o.reservationGCClientContextPrefix,
+		"Prefix of the client contexts rendered from the clientContextTemplate of pools that marks reservations as "+
+			"owned by this management cluster. Only orphaned reservations with this prefix are released, others are "+
+			"only reported. If empty, no reservation is released",
+	)
+	fs.Float64Var(
+		&o.prismCentralQPS,
+		"prism-central-qps",
+		o.prismCentralQPS,
+		"Maximum sustained rate of requests per second sent to each Prism Central. Zero disables rate limiting",
+	)
+	fs.IntVar(
+		&o.prismCentralBurst,
+		"prism-central-burst",
+		o.prismCentralBurst,
+		"Maximum number of requests sent to each Prism Central at once above the sustained rate",
+	)
+	fs.IntVar(
+		&o.prismCentralMaxInFlight,
+		"prism-central-max-in-flight",
+		o.prismCentralMaxInFlight,
+		"Maximum number of concurrent requests to each Prism Central. Zero disables the limit",
+	)
+	fs.IntVar(
+		&o.prismCentralCircuitBreakerThreshold,
+		"prism-central-circuit-breaker-threshold",
+		o.prismCentralCircuitBreakerThreshold,
+		"Number of consecutive transport errors or 5xx responses from a Prism Central after which requests to it "+
+			"are stopped. Zero disables the circuit breaker",
+	)
+	fs.DurationVar(
+		&o.prismCentralCircuitBreakerCoolOff,
+		"prism-central-circuit-breaker-cool-off",
+		o.prismCentralCircuitBreakerCoolOff,
+		"Time requests to a failing Prism Central are stopped for before a probe request is sent",
+	)
 }
 
 func NewNutanixProviderAdapter(
@@ -95,6 +244,7 @@ func NewNutanixProviderAdapter(
 	watchFilter string,
 	secretInformer coreinformers.SecretInformer,
 	cmInformer coreinformers.ConfigMapInformer,
+	recorder events.EventRecorder,
 	opts reconcilerOptions,
 ) *NutanixProviderAdapter {
 	return &NutanixProviderAdapter{
@@ -103,6 +253,7 @@ func NewNutanixProviderAdapter(
 		watchFilterValue: watchFilter,
 		secretInformer:   secretInformer,
 		cmInformer:       cmInformer,
+		recorder:         recorder,
 		opts:             opts,
 	}
 }
@@ -115,6 +266,7 @@ type IPAddressClaimHandler struct {
 	pcClientGetter func(pcclient.CachedClientParams) (pcclient.Client, error)
 	secretInformer coreinformers.SecretInformer
 	cmInformer     coreinformers.ConfigMapInformer
+	recorder       events.EventRecorder
 }
 
 var _ ipamutil.ClaimHandler = &IPAddressClaimHandler{}
@@ -128,18 +280,33 @@ func (i *NutanixProviderAdapter) SetupWithManager(_ context.Context, b *ctrl.Bui
 					Group: v1alpha1.GroupVersion.Group,
 					Kind:  v1alpha1.NutanixIPPoolKind,
 				}),
+				ipampredicates.ClaimReferencesPoolKind(metav1.GroupKind{
+					Group: v1alpha1.GroupVersion.Group,
+					Kind:  v1alpha1.GlobalNutanixIPPoolKind,
+				}),
 			),
 		)).
 		Watches(
 			&v1alpha1.NutanixIPPool{},
 			handler.EnqueueRequestsFromMapFunc(i.ipPoolToIPClaims(v1alpha1.NutanixIPPoolKind)),
-			builder.WithPredicates(resourceUnpaused()),
+			builder.WithPredicates(predicate.Or(resourceUnpaused(), poolBecameReady())),
+		).
+		Watches(
+			&v1alpha1.GlobalNutanixIPPool{},
+			handler.EnqueueRequestsFromMapFunc(i.ipPoolToIPClaims(v1alpha1.GlobalNutanixIPPoolKind)),
+			builder.WithPredicates(predicate.Or(resourceUnpaused(), poolBecameReady())),
 		).
 		Owns(&ipamv1.IPAddress{}, builder.WithPredicates(
-			ipampredicates.AddressReferencesPoolKind(metav1.GroupKind{
-				Group: v1alpha1.GroupVersion.Group,
-				Kind:  v1alpha1.NutanixIPPoolKind,
-			}),
+			predicate.Or(
+				ipampredicates.AddressReferencesPoolKind(metav1.GroupKind{
+					Group: v1alpha1.GroupVersion.Group,
+					Kind:  v1alpha1.NutanixIPPoolKind,
+				}),
+				ipampredicates.AddressReferencesPoolKind(metav1.GroupKind{
+					Group: v1alpha1.GroupVersion.Group,
+					Kind:  v1alpha1.GlobalNutanixIPPoolKind,
+				}),
+			),
 		)).
 		WithOptions(
 			controller.Options{
@@ -165,6 +332,7 @@ func (i *NutanixProviderAdapter) ipPoolToIPClaims(
 	return func(ctx context.Context, a ctrlclient.Object) []reconcile.Request {
 		pool := a.(genericNutanixIPPool)
 		claims := &ipamv1.IPAddressClaimList{}
+		// A GlobalNutanixIPPool has no namespace, so claims referencing it are listed across all namespaces.
 		err := i.k8sClient.List(ctx, claims,
 			ctrlclient.MatchingFields{
 				"index.poolRef": index.IPPoolRefValue(ipamv1.IPPoolReference{
@@ -201,11 +369,12 @@ func (i *NutanixProviderAdapter) ClaimHandlerFor(
 		pcClientGetter: i.pcClientGetter,
 		secretInformer: i.secretInformer,
 		cmInformer:     i.cmInformer,
+		recorder:       i.recorder,
 	}
 }
 
-// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=nutanixippools,verbs=get;list;watch
-// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=nutanixippools/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=nutanixippools;globalnutanixippools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=nutanixippools/finalizers;globalnutanixippools/finalizers,verbs=update
 // +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims/status;ipaddresses/status,verbs=get;update;patch
@@ -213,22 +382,69 @@ func (i *NutanixProviderAdapter) ClaimHandlerFor(
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
 
-// FetchPool fetches the NutanixIPPool.
+// FetchPool fetches the NutanixIPPool or GlobalNutanixIPPool. Claims that are paused are skipped before the pool
+// is fetched, so that no IP is reserved or released for them while their Cluster is moved to another management
+// cluster. Claims of a paused Cluster are already skipped by the ipamutil ClaimReconciler before FetchPool is called.
 func (h *IPAddressClaimHandler) FetchPool(
 	ctx context.Context,
 ) (ctrlclient.Object, *ctrl.Result, error) {
-	if h.claim.Spec.PoolRef.Kind == v1alpha1.NutanixIPPoolKind {
+	if h.reconcilePaused(ctx) {
+		return nil, &ctrl.Result{}, nil
+	}
+
+	switch h.claim.Spec.PoolRef.Kind {
+	case v1alpha1.NutanixIPPoolKind:
 		h.pool = &v1alpha1.NutanixIPPool{}
 		if err := h.client.Get(
 			ctx, types.NamespacedName{Namespace: h.claim.Namespace, Name: h.claim.Spec.PoolRef.Name}, h.pool,
 		); err != nil {
 			return nil, nil, errors.Wrap(err, "failed to fetch pool")
 		}
+	case v1alpha1.GlobalNutanixIPPoolKind:
+		h.pool = &v1alpha1.GlobalNutanixIPPool{}
+		if err := h.client.Get(
+			ctx, types.NamespacedName{Name: h.claim.Spec.PoolRef.Name}, h.pool,
+		); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to fetch pool")
+		}
 	}
 
 	return h.pool, nil, nil
 }
 
+// reconcilePaused sets the Paused condition of the claim from its paused annotation and returns whether the claim is
+// paused. The ipamutil ClaimReconciler still reconciles paused claims when their IPAddress or pool changes, but skips
+// claims of a paused Cluster before calling the handler, so the condition only reflects the annotation of the claim.
+func (h *IPAddressClaimHandler) reconcilePaused(ctx context.Context) bool {
+	if !annotations.HasPaused(h.claim) {
+		conditions.Set(h.claim, metav1.Condition{
+			Type:   clusterv1.PausedCondition,
+			Status: metav1.ConditionFalse,
+			Reason: clusterv1.NotPausedReason,
+		})
+		return false
+	}
+
+	message := fmt.Sprintf("IPAddressClaim has the %s annotation", clusterv1.PausedAnnotation)
+	conditions.Set(h.claim, metav1.Condition{
+		Type:    clusterv1.PausedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  clusterv1.PausedReason,
+		Message: message,
+	})
+	log.FromContext(ctx).Info("Reconciliation is paused for this claim", "reason", message)
+	return true
+}
+
+// claimClusterName returns the name of the Cluster the claim belongs to, as referenced by its clusterName or its
+// cluster name label. It returns an empty string if the claim does not belong to a Cluster.
+func claimClusterName(claim *ipamv1.IPAddressClaim) string {
+	if claim.Spec.ClusterName != "" {
+		return claim.Spec.ClusterName
+	}
+	return claim.Labels[clusterv1.ClusterNameLabel]
+}
+
 // EnsureAddress ensures that the IPAddress contains a valid address.
 func (h *IPAddressClaimHandler) EnsureAddress(
 	ctx context.Context,
@@ -236,8 +452,13 @@ func (h *IPAddressClaimHandler) EnsureAddress(
 ) (*ctrl.Result, error) {
 	// Check if the address already exists.
 	err := h.client.Get(ctx, ctrlclient.ObjectKeyFromObject(address), address)
-	// A nil error means the address already exists so nothing to do.
+	// A nil error means the address already exists so nothing to do, apart from recording the client context on
+	// addresses reserved before it was recorded.
 	if err == nil {
+		clientContext := h.addressClientContext(address)
+		metav1.SetMetaDataAnnotation(&h.claim.ObjectMeta, v1alpha1.ClientContextAnnotation, clientContext)
+		metav1.SetMetaDataAnnotation(&address.ObjectMeta, v1alpha1.ClientContextAnnotation, clientContext)
+		markClaimReady(h.claim)
 		return nil, nil
 	}
 	// If any other error than NotFound, return the error.
@@ -245,39 +466,614 @@ func (h *IPAddressClaimHandler) EnsureAddress(
 		return nil, fmt.Errorf("failed to check for existing IPAddress: %w", err)
 	}
 
+	// Refuse to reserve an IP against a pool that is known to be unusable, surfacing the reason on the claim.
+	// A pool that has not been reconciled yet has no Ready condition and is not blocked. A pool that is being
+	// deleted is blocked too, as its deletion waits for all of its IPAddresses to be deleted.
+	var message string
+	switch {
+	case !h.pool.GetDeletionTimestamp().IsZero():
+		message = fmt.Sprintf("%s %s is being deleted", h.claim.Spec.PoolRef.Kind, h.pool.GetName())
+	case conditions.IsFalse(h.pool, v1alpha1.NutanixIPPoolReadyCondition):
+		message = fmt.Sprintf(
+			"%s %s is not ready: %s",
+			h.claim.Spec.PoolRef.Kind,
+			h.pool.GetName(),
+			conditions.GetMessage(h.pool, v1alpha1.NutanixIPPoolReadyCondition),
+		)
+	}
+	if message != "" {
+		conditions.Set(h.claim, metav1.Condition{
+			Type:    ipamv1.IPAddressClaimReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  ipamv1.IPAddressClaimReadyPoolNotReadyReason,
+			Message: message,
+		})
+		return nil, errors.New(message)
+	}
+
+	// Record the client context the IP is reserved with on the claim before reserving it, so that the IP can still
+	// be adopted and released once clusterctl move recreates the claim with another UID.
+	if err := h.ensureClientContext(); err != nil {
+		markClaimAllocationFailed(h.claim, err.Error())
+		return nil, err
+	}
+
+	requestedAddress, err := h.requestedAddress()
+	if err != nil {
+		markClaimAllocationFailed(h.claim, err.Error())
+		return nil, err
+	}
+
+	family, subnets, err := h.claimSubnets(requestedAddress)
+	if err != nil {
+		markClaimAllocationFailed(h.claim, err.Error())
+		return nil, err
+	}
+
+	ctx = pcclient.WithPool(ctx, poolMetricLabel(h.pool))
 	nutanixClient, err := h.getClient()
 	if err != nil {
+		h.recordEvent(corev1.EventTypeWarning, CredentialsInvalidReason, "Reserve",
+			"Failed to get Prism Central client: %v", err)
 		return nil, fmt.Errorf("failed to get Nutanix client: %w", err)
 	}
-	cluster := ptr.Deref(h.pool.PoolSpec().Cluster, "")
-	subnet, err := nutanixClient.Networking().GetSubnet(
-		ctx,
-		h.pool.PoolSpec().Subnet,
-		pcclient.GetSubnetOpts{Cluster: cluster},
-	)
+
+	allowed, excluded, err := poolAddressRestrictions(h.pool.PoolSpec())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get subnet: %w", err)
+		markClaimAllocationFailed(h.claim, err.Error())
+		return nil, err
+	}
+	restricted := allowed != nil || excluded != nil
+
+	// Adopt any IP already reserved for this claim in any of the subnets, e.g. if a previous reconcile reserved
+	// an IP but failed before the IPAddress was created. Reserving another IP would leak the existing reservation.
+	// If a specific address is requested or the pool restricts the addresses to allocate, all reservations are
+	// listed to detect the addresses reserved by others.
+	// Fallback subnets that cannot be resolved are skipped, as reported by the FallbackSubnetsResolved condition of
+	// the pool, so that they do not block allocating from the other subnets.
+	candidates := make([]subnetCandidate, 0, len(subnets))
+	for i, s := range subnets {
+		subnet, err := nutanixClient.Networking().GetSubnet(
+			ctx,
+			s.name,
+			pcclient.GetSubnetOpts{Cluster: s.cluster},
+		)
+		if err != nil {
+			h.recordEvent(corev1.EventTypeWarning, SubnetResolutionFailedReason, "Reserve",
+				"Failed to resolve subnet %s: %v", s.name, err)
+			if i > 0 {
+				log.FromContext(ctx).Info(
+					"Skipping fallback subnet that cannot be resolved", "subnet", s.name, "error", err.Error(),
+				)
+				continue
+			}
+			err = fmt.Errorf("failed to get subnet: %w", err)
+			if errors.Is(err, pcclient.ErrAmbiguousName) {
+				return nil, h.allocationError(err)
+			}
+			return nil, err
+		}
+		// Pools without an IPv6Subnet allocate both IP families from Subnet, which must then be of the requested
+		// family, e.g. an IPv6 claim of a pool whose Subnet is an IPv4 subnet must not be allocated an IPv4 address.
+		if family != "" && !subnet.HasIPConfigFor(familyAddr(family)) {
+			err := fmt.Errorf("subnet %s has no %s configuration to allocate the claim's address from", s.name, family)
+			if i > 0 {
+				log.FromContext(ctx).Info("Skipping fallback subnet of another IP family", "subnet", s.name)
+				continue
+			}
+			h.recordEvent(corev1.EventTypeWarning, IPReservationFailedReason, "Reserve", "%v", err)
+			return nil, h.allocationError(err)
+		}
+
+		ownedIPs, reservedByOthers, err := h.existingReservations(
+			ctx, nutanixClient, s, requestedAddress, requestedAddress.IsValid() || restricted,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(ownedIPs) > 0 {
+			h.recordEvent(corev1.EventTypeNormal, IPReservationAdoptedReason, "Reserve",
+				"Adopted IP %s already reserved in subnet %s (%s)", ownedIPs[0], s.name, subnet.ExtID())
+			h.releaseExtraReservations(ctx, nutanixClient, subnet, s.name, ownedIPs[1:])
+			h.setAddress(address, subnet, ownedIPs[0])
+			markClaimReady(h.claim)
+			return nil, nil
+		}
+
+		candidates = append(candidates, subnetCandidate{
+			poolSubnet:       s,
+			subnet:           subnet,
+			reservedByOthers: reservedByOthers,
+		})
+	}
+	if requestedAddress.IsValid() {
+		candidates = []subnetCandidate{requestedAddressCandidate(candidates, requestedAddress)}
+	}
+
+	// Reserve the IP in the first subnet that has a free IP, falling back to the next subnet of the pool when a
+	// subnet is exhausted.
+	for i, candidate := range candidates {
+		reservedIP, err := h.reserveAddress(ctx, nutanixClient, candidate, requestedAddress, allowed, excluded)
+		if err != nil {
+			if i < len(candidates)-1 && (errors.Is(err, errNoFreeIP) || errors.Is(err, pcclient.ErrPoolExhausted)) {
+				log.FromContext(ctx).Info(
+					"Subnet has no free IPs, falling back to the next subnet of the pool",
+					"subnet", candidate.name,
+					"nextSubnet", candidates[i+1].name,
+				)
+				continue
+			}
+			h.recordEvent(corev1.EventTypeWarning, IPReservationFailedReason, "Reserve",
+				"Failed to reserve IP in subnet %s: %v", candidate.name, err)
+			return nil, h.allocationError(fmt.Errorf("failed to reserve IP: %w", err))
+		}
+
+		h.recordEvent(corev1.EventTypeNormal, IPReservedReason, "Reserve",
+			"Reserved IP %s in subnet %s (%s)", reservedIP, candidate.name, candidate.subnet.ExtID())
+		h.setAddress(address, candidate.subnet, reservedIP)
+		break
+	}
+
+	markClaimReady(h.claim)
+
+	return nil, nil
+}
+
+// poolSubnet is a Nutanix subnet of a pool, along with the PE cluster used to resolve its name.
+type poolSubnet struct {
+	name    string
+	cluster string
+}
+
+// subnetCandidate is a resolved pool subnet to reserve a claim's address in.
+type subnetCandidate struct {
+	poolSubnet
+	subnet           *pcclient.Subnet
+	reservedByOthers *netipx.IPSet
+}
+
+// existingReservations lists the IPs reserved in the subnet. It returns the IPs reserved for the claim, limited to
+// the requested address if set, and the IPs reserved by others. The IPs reserved by others are only listed if
+// listAll is set, otherwise only the reservations of the claim are listed.
+func (h *IPAddressClaimHandler) existingReservations(
+	ctx context.Context,
+	nutanixClient pcclient.Client,
+	subnet poolSubnet,
+	requestedAddress netip.Addr,
+	listAll bool,
+) (ownedIPs []netip.Addr, reservedByOthers *netipx.IPSet, err error) {
+	listOpts := pcclient.ListReservedIPsOpts{
+		Cluster:       subnet.cluster,
+		ClientContext: h.clientContext(),
+	}
+	if listAll {
+		listOpts.ClientContext = ""
+	}
+	reservations, err := nutanixClient.Networking().ListReservedIPs(ctx, subnet.name, listOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list reserved IPs: %w", err)
+	}
+
+	var others netipx.IPSetBuilder
+	for _, reservation := range reservations {
+		ownedByClaim := listOpts.ClientContext != "" || reservation.ClientContext == h.clientContext()
+		switch {
+		case !ownedByClaim:
+			others.Add(reservation.Address)
+		case !requestedAddress.IsValid() || reservation.Address == requestedAddress:
+			ownedIPs = append(ownedIPs, reservation.Address)
+		}
+	}
+
+	reservedByOthers, err = others.IPSet()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build set of reserved IPs: %w", err)
+	}
+	return ownedIPs, reservedByOthers, nil
+}
+
+// releaseExtraReservations releases the IPs reserved for the claim in the subnet besides the adopted one, e.g. if
+// concurrent reconciles of the claim each reserved an IP, so that they do not stay reserved until the claim is
+// deleted. Failing to release them does not fail the claim, as they are still released by client context along
+// with the adopted IP when the claim is deleted.
+func (h *IPAddressClaimHandler) releaseExtraReservations(
+	ctx context.Context,
+	nutanixClient pcclient.Client,
+	subnet *pcclient.Subnet,
+	subnetName string,
+	extraIPs []netip.Addr,
+) {
+	if len(extraIPs) == 0 {
+		return
+	}
+
+	ips := make([]string, 0, len(extraIPs))
+	for _, ip := range extraIPs {
+		ips = append(ips, ip.String())
+	}
+	unreserveType, err := pcclient.UnreserveIPListFunc(ips...)
+	if err == nil {
+		_, err = nutanixClient.Networking().UnreserveIPsInSubnet(ctx, unreserveType, subnet)
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to release extra IPs reserved for the claim",
+			"subnet", subnetName, "addresses", ips)
+		h.recordEvent(corev1.EventTypeWarning, IPReleaseFailedReason, "Release",
+			"Failed to release extra IPs %v reserved in subnet %s: %v", ips, subnetName, err)
+		return
+	}
+	h.recordEvent(corev1.EventTypeNormal, IPReleasedReason, "Release",
+		"Released extra IPs %v reserved in subnet %s", ips, subnetName)
+}
+
+// maxInUseAddressAttempts is the maximum number of IPs picked by the provider that are tried in a subnet when Prism
+// Central rejects them as in use, e.g. because they are assigned to VM NICs without being reserved.
+const maxInUseAddressAttempts = 5
+
+// reserveAddress reserves an IP for the claim in the candidate subnet and returns it. If the pool restricts the
+// addresses to allocate and Prism Central rejects the picked IP as already in use, the next free IP is tried.
+func (h *IPAddressClaimHandler) reserveAddress(
+	ctx context.Context,
+	nutanixClient pcclient.Client,
+	candidate subnetCandidate,
+	requestedAddress netip.Addr,
+	allowed, excluded *netipx.IPSet,
+) (netip.Addr, error) {
+	reserved := candidate.reservedByOthers
+	var inUse []netip.Addr
+	for {
+		addr, err := addressToReserve(candidate.subnet, candidate.name, requestedAddress, allowed, excluded, reserved)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+		reservedIP, err := h.reserveAddressInSubnet(ctx, nutanixClient, candidate, addr)
+		if err == nil || requestedAddress.IsValid() || !addr.IsValid() ||
+			!errors.Is(err, pcclient.ErrAddressAlreadyReserved) {
+			return reservedIP, err
+		}
+
+		// The IP was picked by the provider rather than requested, so it is not an error of the claim that it is
+		// in use: try the next free IP instead of failing the claim terminally.
+		inUse = append(inUse, addr)
+		if len(inUse) >= maxInUseAddressAttempts {
+			return netip.Addr{}, fmt.Errorf(
+				"IPs %v picked in subnet %s are already in use: %v", inUse, candidate.name, err,
+			)
+		}
+		log.FromContext(ctx).Info(
+			"IP picked for the claim is already in use, trying the next free IP",
+			"subnet", candidate.name,
+			"address", addr,
+		)
+		var builder netipx.IPSetBuilder
+		builder.AddSet(reserved)
+		builder.Add(addr)
+		if reserved, err = builder.IPSet(); err != nil {
+			return netip.Addr{}, fmt.Errorf("failed to build set of reserved IPs: %w", err)
+		}
+	}
+}
+
+// reserveAddressInSubnet reserves the IP in the candidate subnet, or any free IP of the subnet if the IP is invalid,
+// and returns the reserved IP.
+func (h *IPAddressClaimHandler) reserveAddressInSubnet(
+	ctx context.Context,
+	nutanixClient pcclient.Client,
+	candidate subnetCandidate,
+	addr netip.Addr,
+) (netip.Addr, error) {
+	reserveType := pcclient.ReserveIPCountFunc(1)
+	if addr.IsValid() {
+		listType, err := pcclient.ReserveIPListFunc(addr.String())
+		if err != nil {
+			return netip.Addr{}, err
+		}
+		reserveType = listType
 	}
 
 	// Reserve the IP address. This blocks until the underlying Prism task
 	// completes and returns the reserved IPs.
-	reservedIPs, err := nutanixClient.Networking().ReserveIPs(
+	reservedIPs, err := nutanixClient.Networking().ReserveIPsInSubnet(
 		ctx,
-		pcclient.ReserveIPCountFunc(1),
-		h.pool.PoolSpec().Subnet,
+		reserveType,
+		candidate.subnet,
 		pcclient.ReserveIPOpts{
-			Cluster:       cluster,
-			ClientContext: string(h.claim.UID),
+			ClientContext: h.clientContext(),
 		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to reserve IP: %w", err)
+		return netip.Addr{}, err
 	}
+	if len(reservedIPs) == 0 {
+		return netip.Addr{}, fmt.Errorf("no IP reserved in subnet %s", candidate.name)
+	}
+	return reservedIPs[0], nil
+}
 
-	address.Spec.Address = reservedIPs[0].String()
-	address.Spec.Prefix = ptr.To(subnet.Prefix())
+// requestedAddressCandidate returns the first candidate subnet whose IP pools contain the requested address, or
+// the first candidate if none does, e.g. because the address is outside of the IP pools of all subnets.
+func requestedAddressCandidate(candidates []subnetCandidate, requestedAddress netip.Addr) subnetCandidate {
+	for _, candidate := range candidates {
+		if candidate.subnet.Pools().Contains(requestedAddress) {
+			return candidate
+		}
+	}
+	return candidates[0]
+}
 
-	return nil, nil
+// setAddress sets the reserved IP on the IPAddress and records the subnet it was reserved in, so that it is
+// released from the same subnet.
+func (h *IPAddressClaimHandler) setAddress(address *ipamv1.IPAddress, subnet *pcclient.Subnet, ip netip.Addr) {
+	address.Spec.Address = ip.String()
+	address.Spec.Prefix = ptr.To(subnet.PrefixFor(ip))
+	if gateway := h.gatewayFor(subnet, ip); gateway.IsValid() {
+		address.Spec.Gateway = gateway.String()
+	}
+	metav1.SetMetaDataAnnotation(&address.ObjectMeta, v1alpha1.SubnetAnnotation, subnet.ExtID().String())
+	metav1.SetMetaDataAnnotation(&address.ObjectMeta, v1alpha1.ClientContextAnnotation, h.clientContext())
+}
+
+// clientContext returns the client context of the claim's reservations in Prism Central.
+func (h *IPAddressClaimHandler) clientContext() string {
+	return claimClientContext(h.claim)
+}
+
+// ensureClientContext records the client context to reserve the claim's IP with on the claim, rendered from the
+// ClientContextTemplate of the pool, unless a client context is already recorded, e.g. on a claim moved with
+// clusterctl move.
+func (h *IPAddressClaimHandler) ensureClientContext() error {
+	if h.claim.GetAnnotations()[v1alpha1.ClientContextAnnotation] != "" {
+		return nil
+	}
+
+	tmpl, err := clientcontext.Parse(ptr.Deref(h.pool.PoolSpec().ClientContextTemplate, ""))
+	if err != nil {
+		return fmt.Errorf("invalid clientContextTemplate of pool: %w", err)
+	}
+	clientContext, err := tmpl.Execute(clientcontext.Fields{
+		ClusterName: claimClusterName(h.claim),
+		Namespace:   h.claim.Namespace,
+		Name:        h.claim.Name,
+		UID:         string(h.claim.UID),
+	})
+	if err != nil {
+		return err
+	}
+	metav1.SetMetaDataAnnotation(&h.claim.ObjectMeta, v1alpha1.ClientContextAnnotation, clientContext)
+	return nil
+}
+
+// claimClientContext returns the client context of the claim's reservations in Prism Central: the
+// ClientContextAnnotation of the claim if set, or the UID of the claim otherwise.
+func claimClientContext(claim *ipamv1.IPAddressClaim) string {
+	if clientContext := claim.GetAnnotations()[v1alpha1.ClientContextAnnotation]; clientContext != "" {
+		return clientContext
+	}
+	return string(claim.UID)
+}
+
+// addressClientContext returns the client context the address was reserved with in Prism Central. The client
+// context recorded on the address is preferred, falling back to the client context of the claim.
+func (h *IPAddressClaimHandler) addressClientContext(address *ipamv1.IPAddress) string {
+	if clientContext := address.GetAnnotations()[v1alpha1.ClientContextAnnotation]; clientContext != "" {
+		return clientContext
+	}
+	return h.clientContext()
+}
+
+// poolAddressRestrictions returns the IPs the pool allows to allocate and the IPs it excludes from allocation.
+// Either IPSet is nil if the pool does not restrict the addresses to allocate in that way.
+func poolAddressRestrictions(spec *v1alpha1.NutanixIPPoolSpec) (allowed, excluded *netipx.IPSet, err error) {
+	allowed, err = poolutil.AddressesToIPSet(spec.AllowedRanges)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid allowedRanges of pool: %w", err)
+	}
+	excluded, err = poolutil.AddressesToIPSet(spec.ExcludedAddresses)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid excludedAddresses of pool: %w", err)
+	}
+	return allowed, excluded, nil
+}
+
+// errNoFreeIP is returned by addressToReserve if the subnet has no free IP that the pool allows to allocate.
+var errNoFreeIP = errors.New("no free IP")
+
+// addressToReserve returns the IP to reserve for a claim: the requested address, or the lowest free IP of the
+// subnet's IP pools allowed by the pool. The returned address is invalid if the pool does not restrict the
+// addresses to allocate, in which case any free IP of the subnet can be reserved. IPs reserved by others are
+// never returned.
+func addressToReserve(
+	subnet *pcclient.Subnet,
+	subnetName string,
+	requestedAddress netip.Addr,
+	allowed, excluded, reserved *netipx.IPSet,
+) (netip.Addr, error) {
+	if requestedAddress.IsValid() {
+		switch {
+		case reserved.Contains(requestedAddress):
+			return netip.Addr{}, fmt.Errorf(
+				"requested IP %s is already reserved in subnet %s: %w",
+				requestedAddress, subnetName, pcclient.ErrAddressAlreadyReserved,
+			)
+		case allowed != nil && !allowed.Contains(requestedAddress):
+			return netip.Addr{}, fmt.Errorf(
+				"requested IP %s is not within the allowedRanges of the pool", requestedAddress,
+			)
+		case excluded != nil && excluded.Contains(requestedAddress):
+			return netip.Addr{}, fmt.Errorf(
+				"requested IP %s is in the excludedAddresses of the pool", requestedAddress,
+			)
+		}
+		return requestedAddress, nil
+	}
+
+	if allowed == nil && excluded == nil {
+		return netip.Addr{}, nil
+	}
+
+	if len(subnet.Pools().Ranges()) == 0 {
+		return netip.Addr{}, fmt.Errorf(
+			"subnet %s has no IP pools to allocate the allowed IPs of the pool from", subnetName,
+		)
+	}
+	free, err := poolutil.FreeIPSet(subnet.Pools(), allowed, excluded, reserved)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("failed to compute free IPs in subnet %s: %w", subnetName, err)
+	}
+	addr := poolutil.FirstIP(free)
+	if !addr.IsValid() {
+		return netip.Addr{}, fmt.Errorf("%w within the allowed IPs of the pool in subnet %s", errNoFreeIP, subnetName)
+	}
+	return addr, nil
+}
+
+// requestedAddress returns the address requested via the RequestedAddressAnnotation. The returned address is
+// invalid if no address is requested.
+func (h *IPAddressClaimHandler) requestedAddress() (netip.Addr, error) {
+	value, ok := h.claim.GetAnnotations()[v1alpha1.RequestedAddressAnnotation]
+	if !ok {
+		return netip.Addr{}, nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf(
+			"invalid %s annotation value %q: %w", v1alpha1.RequestedAddressAnnotation, value, err,
+		)
+	}
+	return addr.Unmap(), nil
+}
+
+// claimSubnets returns the IP family requested via the IPFamilyAnnotation or by the family of the requested address,
+// if any, and the pool subnets to allocate the claim's address from, in order, selected by that family. Pools
+// without an IPv6Subnet always allocate from Subnet and its fallback subnets.
+func (h *IPAddressClaimHandler) claimSubnets(requestedAddress netip.Addr) (corev1.IPFamily, []poolSubnet, error) {
+	spec := h.pool.PoolSpec()
+
+	family, ok := h.claim.GetAnnotations()[v1alpha1.IPFamilyAnnotation]
+	if requestedAddress.IsValid() {
+		requestedFamily := corev1.IPv4Protocol
+		if requestedAddress.Is6() {
+			requestedFamily = corev1.IPv6Protocol
+		}
+		if ok && corev1.IPFamily(family) != requestedFamily {
+			return "", nil, fmt.Errorf(
+				"%s annotation value %q does not match the IP family of the requested address %s",
+				v1alpha1.IPFamilyAnnotation,
+				family,
+				requestedAddress,
+			)
+		}
+		family, ok = string(requestedFamily), true
+	}
+	if !ok {
+		return "", allocationSubnets(spec), nil
+	}
+
+	switch corev1.IPFamily(family) {
+	case corev1.IPv4Protocol:
+		return corev1.IPv4Protocol, allocationSubnets(spec), nil
+	case corev1.IPv6Protocol:
+		if spec.IPv6Subnet != nil {
+			ipv6Subnet := poolSubnet{name: *spec.IPv6Subnet, cluster: ptr.Deref(spec.Cluster, "")}
+			return corev1.IPv6Protocol, []poolSubnet{ipv6Subnet}, nil
+		}
+		return corev1.IPv6Protocol, allocationSubnets(spec), nil
+	default:
+		return "", nil, fmt.Errorf(
+			"invalid %s annotation value %q: must be one of %s or %s",
+			v1alpha1.IPFamilyAnnotation,
+			family,
+			corev1.IPv4Protocol,
+			corev1.IPv6Protocol,
+		)
+	}
+}
+
+// familyAddr returns an address of the IP family, to match subnet configurations against.
+func familyAddr(family corev1.IPFamily) netip.Addr {
+	if family == corev1.IPv6Protocol {
+		return netip.IPv6Unspecified()
+	}
+	return netip.IPv4Unspecified()
+}
+
+// allocationSubnets returns the pool's Subnet followed by its fallback subnets, in allocation order.
+func allocationSubnets(spec *v1alpha1.NutanixIPPoolSpec) []poolSubnet {
+	cluster := ptr.Deref(spec.Cluster, "")
+	subnets := make([]poolSubnet, 0, 1+len(spec.FallbackSubnets))
+	subnets = append(subnets, poolSubnet{name: spec.Subnet, cluster: cluster})
+	for _, fallback := range spec.FallbackSubnets {
+		subnets = append(subnets, poolSubnet{name: fallback.Subnet, cluster: ptr.Deref(fallback.Cluster, cluster)})
+	}
+	return subnets
+}
+
+// gatewayFor returns the gateway for the given address, preferring the pool's Gateway override if it is of the
+// same IP family as the address. The returned address is invalid if no gateway is known for the address family.
+func (h *IPAddressClaimHandler) gatewayFor(subnet *pcclient.Subnet, addr netip.Addr) netip.Addr {
+	if override := h.pool.PoolSpec().Gateway; override != nil {
+		gateway, err := netip.ParseAddr(*override)
+		if err == nil && gateway.Is4() == addr.Is4() {
+			return gateway
+		}
+	}
+	return subnet.GatewayFor(addr)
+}
+
+// addressSubnet returns the pool subnet that the address was allocated from. The subnet recorded on the address is
+// preferred, as the address may have been allocated from a fallback subnet.
+func (h *IPAddressClaimHandler) addressSubnet(address *ipamv1.IPAddress) poolSubnet {
+	if extID := address.GetAnnotations()[v1alpha1.SubnetAnnotation]; extID != "" {
+		return poolSubnet{name: extID}
+	}
+
+	spec := h.pool.PoolSpec()
+	cluster := ptr.Deref(spec.Cluster, "")
+	if spec.IPv6Subnet == nil {
+		return poolSubnet{name: spec.Subnet, cluster: cluster}
+	}
+
+	addr, err := netip.ParseAddr(address.Spec.Address)
+	if err == nil && addr.Is6() {
+		return poolSubnet{name: *spec.IPv6Subnet, cluster: cluster}
+	}
+	return poolSubnet{name: spec.Subnet, cluster: cluster}
+}
+
+// allocationError marks the claim as failed to allocate an address and returns the error to return from
+// EnsureAddress. Transient errors, throttled requests and requests rejected by the circuit breaker are retried with
+// backoff without marking the claim as failed. Errors that cannot be resolved by retrying, e.g. a requested address
+// that is reserved by others, are returned as terminal errors, so that the claim is only reconciled again once it or
+// its pool changes.
+func (h *IPAddressClaimHandler) allocationError(err error) error {
+	if errors.Is(err, pcclient.ErrTransient) ||
+		errors.Is(err, pcclient.ErrThrottled) ||
+		errors.Is(err, pcclient.ErrCircuitOpen) {
+		return err
+	}
+	markClaimAllocationFailed(h.claim, err.Error())
+	if errors.Is(err, pcclient.ErrAddressAlreadyReserved) || errors.Is(err, pcclient.ErrAmbiguousName) {
+		return reconcile.TerminalError(err)
+	}
+	return err
+}
+
+func markClaimReady(claim *ipamv1.IPAddressClaim) {
+	conditions.Set(claim, metav1.Condition{
+		Type:   ipamv1.IPAddressClaimReadyCondition,
+		Status: metav1.ConditionTrue,
+		Reason: clusterv1.ReadyReason,
+	})
+}
+
+func markClaimAllocationFailed(claim *ipamv1.IPAddressClaim, message string) {
+	conditions.Set(claim, metav1.Condition{
+		Type:    ipamv1.IPAddressClaimReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  ipamv1.IPAddressClaimReadyAllocationFailedReason,
+		Message: message,
+	})
 }
 
 // ReleaseAddress releases the ip address.
@@ -289,7 +1085,7 @@ func (h *IPAddressClaimHandler) ReleaseAddress(ctx context.Context) (*ctrl.Resul
 	var address ipamv1.IPAddress
 	if err := h.client.Get(
 		ctx,
-		ctrlclient.ObjectKey{Namespace: h.pool.GetNamespace(), Name: h.claim.Status.AddressRef.Name},
+		ctrlclient.ObjectKey{Namespace: h.claim.Namespace, Name: h.claim.Status.AddressRef.Name},
 		&address,
 	); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -299,8 +1095,11 @@ func (h *IPAddressClaimHandler) ReleaseAddress(ctx context.Context) (*ctrl.Resul
 		return nil, fmt.Errorf("failed to get IPAddress: %w", err)
 	}
 
+	ctx = pcclient.WithPool(ctx, poolMetricLabel(h.pool))
 	nutanixClient, err := h.getClient()
 	if err != nil {
+		h.recordEvent(corev1.EventTypeWarning, CredentialsInvalidReason, "Release",
+			"Failed to get Prism Central client: %v", err)
 		return nil, fmt.Errorf("failed to get Nutanix client: %w", err)
 	}
 
@@ -309,27 +1108,44 @@ func (h *IPAddressClaimHandler) ReleaseAddress(ctx context.Context) (*ctrl.Resul
 	// released. Unreserving by context means the server resolves and releases
 	// the IPs, so the returned list is the authoritative record of what was
 	// freed.
+	subnet := h.addressSubnet(&address)
+	clientContext := h.addressClientContext(&address)
 	unreservedIPs, err := nutanixClient.Networking().UnreserveIPs(
 		ctx,
-		pcclient.UnreserveIPClientContext(string(h.claim.UID)),
-		h.pool.PoolSpec().Subnet,
+		pcclient.UnreserveIPClientContext(clientContext),
+		subnet.name,
 		pcclient.UnreserveIPOpts{
-			Cluster: ptr.Deref(h.pool.PoolSpec().Cluster, ""),
+			Cluster: subnet.cluster,
 		},
 	)
 	if err != nil {
+		h.recordEvent(corev1.EventTypeWarning, IPReleaseFailedReason, "Release",
+			"Failed to release IP %s in subnet %s: %v", address.Spec.Address, subnet.name, err)
 		return nil, fmt.Errorf("failed to unreserve IP: %w", err)
 	}
 
 	log.FromContext(ctx).V(1).Info(
 		"Unreserved IP addresses by client context",
-		"clientContext", string(h.claim.UID),
+		"clientContext", clientContext,
 		"unreservedIPs", unreservedIPs,
 	)
+	h.recordEvent(corev1.EventTypeNormal, IPReleasedReason, "Release",
+		"Released IP %s in subnet %s", address.Spec.Address, subnet.name)
 
 	return nil, nil
 }
 
+// recordEvent records an event on the claim and on its pool. The pool event is prefixed with the claim, as a pool
+// is shared by many claims.
+func (h *IPAddressClaimHandler) recordEvent(eventType, reason, action, note string, args ...any) {
+	h.recorder.Eventf(h.claim, h.pool, eventType, reason, action, note, args...)
+	h.recorder.Eventf(
+		h.pool, h.claim, eventType, reason, action,
+		"IPAddressClaim %s: "+note,
+		append([]any{ctrlclient.ObjectKeyFromObject(h.claim)}, args...)...,
+	)
+}
+
 func resourceUnpaused() predicate.Predicate {
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
@@ -347,54 +1163,31 @@ func resourceUnpaused() predicate.Predicate {
 	}
 }
 
-func (h *IPAddressClaimHandler) getClient() (pcclient.Client, error) {
-	pc := h.pool.PoolSpec().PrismCentral
-
-	var additionalTrustBundle *credentials.NutanixTrustBundleReference
-	if pc.AdditionalTrustBundle != nil {
-		switch {
-		case len(pc.AdditionalTrustBundle.Data) > 0:
-			additionalTrustBundle = &credentials.NutanixTrustBundleReference{
-				Data: string(pc.AdditionalTrustBundle.Data),
-				Kind: credentials.NutanixTrustBundleKindString,
-			}
-		case pc.AdditionalTrustBundle.ConfigMapReference != nil && pc.AdditionalTrustBundle.ConfigMapReference.Name != "":
-			additionalTrustBundle = &credentials.NutanixTrustBundleReference{
-				Name:      pc.AdditionalTrustBundle.ConfigMapReference.Name,
-				Namespace: h.pool.GetNamespace(),
-				Kind:      credentials.NutanixTrustBundleKindConfigMap,
+// poolBecameReady returns a predicate that triggers when a pool's Ready condition transitions to true, so that
+// claims refused while the pool was not ready are retried.
+func poolBecameReady() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPool, okOld := e.ObjectOld.(conditions.Getter)
+			newPool, okNew := e.ObjectNew.(conditions.Getter)
+			if !okOld || !okNew {
+				return false
 			}
-		default:
-			return nil, fmt.Errorf(
-				"invalid additional trust bundle configuration: either data or secretRef must be set",
-			)
-		}
-	}
-
-	cacheClientParams, err := newClientCacheParams(
-		credentials.NutanixPrismEndpoint{
-			Address:               pc.Address,
-			Port:                  int32(pc.Port),
-			Insecure:              pc.Insecure,
-			AdditionalTrustBundle: additionalTrustBundle,
-			CredentialRef: &credentials.NutanixCredentialReference{
-				Kind:      credentials.SecretKind,
-				Name:      pc.CredentialsSecretRef.Name,
-				Namespace: h.pool.GetNamespace(),
-			},
+			return !conditions.IsTrue(oldPool, v1alpha1.NutanixIPPoolReadyCondition) &&
+				conditions.IsTrue(newPool, v1alpha1.NutanixIPPoolReadyCondition)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
 		},
-		h.secretInformer,
-		h.cmInformer,
-		h.pool,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Nutanix cache client params: %w", err)
-	}
-
-	c, err := h.pcClientGetter(cacheClientParams)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get Nutanix client: %w", err)
 	}
+}
 
-	return c, nil
+func (h *IPAddressClaimHandler) getClient() (pcclient.Client, error) {
+	return getClientForPool(h.pool, h.pcClientGetter, h.secretInformer, h.cmInformer)
 }
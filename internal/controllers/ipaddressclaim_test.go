@@ -8,7 +8,9 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/netip"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,14 +18,19 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"go4.org/netipx"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-ipam-provider-in-cluster/pkg/ipamutil"
+	clusterv1 "sigs.k8s.io/cluster-api/api/core/v1beta2"
 	ipamv1 "sigs.k8s.io/cluster-api/api/ipam/v1beta2"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/nutanix-cloud-native/prism-go-client/environment/credentials"
 
@@ -77,6 +84,11 @@ var _ = Describe("IPAddressClaimReconciler", func() {
 		DeferCleanup(mockController.Finish)
 
 		mockPCClient = mockclient.NewMockClient(mockController)
+
+		// Drop the events of previous tests.
+		for len(claimEventRecorder.Events) > 0 {
+			<-claimEventRecorder.Events
+		}
 	})
 
 	Context("When a new IPAddressClaim is created", func() {
@@ -157,12 +169,17 @@ var _ = Describe("IPAddressClaimReconciler", func() {
 						gomock.Any(),
 						pool.Spec.Subnet,
 						gomock.Any(),
-					).Return(pcclient.NewSubnet(uuid.New(), 24), nil),
-					mockNC.EXPECT().ReserveIPs(
-						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
 						gomock.Any(),
 						pool.Spec.Subnet,
 						gomock.Any(),
+					).Return(nil, nil),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
 					).Return(
 						[]netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil,
 					),
@@ -180,6 +197,9 @@ var _ = Describe("IPAddressClaimReconciler", func() {
 						Name:       "test",
 						Namespace:  namespace,
 						Finalizers: []string{ipamutil.ProtectAddressFinalizer},
+						Annotations: map[string]string{
+							v1alpha1.SubnetAnnotation: pool.Spec.Subnet,
+						},
 						OwnerReferences: []metav1.OwnerReference{{
 							APIVersion:         ipamv1.GroupVersion.String(),
 							BlockOwnerDeletion: ptr.To(true),
@@ -209,6 +229,7 @@ var _ = Describe("IPAddressClaimReconciler", func() {
 				}
 
 				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+				expectedIPAddress.Annotations[v1alpha1.ClientContextAnnotation] = string(claim.UID)
 
 				Eventually(func(g Gomega) *ipamv1.IPAddress {
 					address := ipamv1.IPAddress{}
@@ -223,126 +244,1258 @@ var _ = Describe("IPAddressClaimReconciler", func() {
 						ignoreUIDsOnIPAddress,
 					),
 				)
+				Eventually(claimEventRecorder.Events).Should(Receive(And(
+					HavePrefix(corev1.EventTypeNormal+" "+IPReservedReason),
+					ContainSubstring("127.0.0.1"),
+				)))
 
 				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+				Eventually(claimEventRecorder.Events).Should(Receive(And(
+					HavePrefix(corev1.EventTypeNormal+" "+IPReleasedReason),
+					ContainSubstring("127.0.0.1"),
+				)))
 			})
 
-			It(
-				"should retry on errors to and recover to allocate an Address from the Pool",
-				func() {
-					mockNC := mockclient.NewMockNetworkingClient(mockController)
-					mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
-					expectedCalls := make([]any, 0, 12)
-					for range 3 {
-						expectedCalls = append(expectedCalls,
-							mockNC.EXPECT().GetSubnet(
-								gomock.Any(),
-								pool.Spec.Subnet,
-								gomock.Any(),
-							).Return(pcclient.NewSubnet(uuid.New(), 24), nil).Call,
-							mockNC.EXPECT().ReserveIPs(
-								gomock.Any(),
-								gomock.Any(),
-								pool.Spec.Subnet,
-								gomock.Any(),
-							).Return(nil, errors.New("task failed")).Call,
-						)
-					}
-					expectedCalls = append(expectedCalls,
-						mockNC.EXPECT().GetSubnet(
-							gomock.Any(),
-							pool.Spec.Subnet,
-							gomock.Any(),
-						).Return(pcclient.NewSubnet(uuid.New(), 24), nil).Call,
-						mockNC.EXPECT().ReserveIPs(
-							gomock.Any(),
-							gomock.Any(),
-							pool.Spec.Subnet,
-							gomock.Any(),
-						).Return([]netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil).Call,
-					)
-					for range 3 {
-						expectedCalls = append(expectedCalls, mockNC.EXPECT().UnreserveIPs(
-							gomock.Any(),
-							gomock.Any(),
-							pool.Spec.Subnet,
-							gomock.Any(),
-						).Return(nil, errors.New("task failed")).Call)
-					}
-					expectedCalls = append(expectedCalls, mockNC.EXPECT().UnreserveIPs(
+			It("should record a warning event when the subnet cannot be resolved", func() {
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				mockNC.EXPECT().GetSubnet(
+					gomock.Any(),
+					pool.Spec.Subnet,
+					gomock.Any(),
+				).Return(nil, errors.New("subnet not found")).MinTimes(1)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+				DeferCleanup(env.CleanupAndWait, context.Background(), &claim)
+
+				Eventually(claimEventRecorder.Events).Should(Receive(And(
+					HavePrefix(corev1.EventTypeWarning+" "+SubnetResolutionFailedReason),
+					ContainSubstring(client.ObjectKeyFromObject(&claim).String()),
+					ContainSubstring("subnet not found"),
+				)))
+			})
+
+			It("should set the Gateway of the Address from the Pool override", func() {
+				pool.Spec.Gateway = ptr.To("10.0.0.254")
+				Expect(env.Update(context.Background(), &pool)).To(Succeed())
+				Eventually(func(g Gomega) *string {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool),
+					).To(Succeed())
+					return pool.Spec.Gateway
+				}).ShouldNot(BeNil())
+
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
 						gomock.Any(),
+					).Return(pcclient.NewSubnet(
+						uuid.MustParse(pool.Spec.Subnet),
+						24,
+						pcclient.WithSubnetGateway(netip.MustParseAddr("10.0.0.1")),
+					), nil),
+					mockNC.EXPECT().ListReservedIPs(
 						gomock.Any(),
 						pool.Spec.Subnet,
 						gomock.Any(),
-					).Return(nil, nil).Call)
-					gomock.InOrder(expectedCalls...)
+					).Return(nil, nil),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return(
+						[]netip.Addr{netip.MustParseAddr("10.0.0.10")}, nil,
+					),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
 
-					claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
-					expectedIPAddress := ipamv1.IPAddress{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:       "test",
-							Namespace:  namespace,
-							Finalizers: []string{ipamutil.ProtectAddressFinalizer},
-							OwnerReferences: []metav1.OwnerReference{{
-								APIVersion:         ipamv1.GroupVersion.String(),
-								BlockOwnerDeletion: ptr.To(true),
-								Controller:         ptr.To(true),
-								Kind:               "IPAddressClaim",
-								Name:               "test",
-							}, {
-								APIVersion:         v1alpha1.GroupVersion.String(),
-								BlockOwnerDeletion: ptr.To(true),
-								Controller:         ptr.To(false),
-								Kind:               v1alpha1.NutanixIPPoolKind,
-								Name:               poolName,
-							}},
-						},
-						Spec: ipamv1.IPAddressSpec{
-							ClaimRef: ipamv1.IPAddressClaimReference{
-								Name: "test",
-							},
-							PoolRef: ipamv1.IPPoolReference{
-								APIGroup: v1alpha1.GroupVersion.Group,
-								Kind:     v1alpha1.NutanixIPPoolKind,
-								Name:     poolName,
-							},
-							Address: "127.0.0.1",
-							Prefix:  ptr.To[int32](24),
-						},
-					}
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
 
-					Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+				Eventually(func(g Gomega) ipamv1.IPAddressSpec {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(And(
+					HaveField("Address", "10.0.0.10"),
+					HaveField("Gateway", "10.0.0.254"),
+				))
 
-					Eventually(func(g Gomega) *ipamv1.IPAddress {
-						address := ipamv1.IPAddress{}
-						g.Expect(
-							env.Get(
-								context.Background(),
-								client.ObjectKeyFromObject(&claim),
-								&address,
-							),
-						).To(Succeed())
-						return &address
-					}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(
-						komega.EqualObject(
-							&expectedIPAddress,
-							komega.IgnoreAutogeneratedMetadata,
-							ignoreUIDsOnIPAddress,
-						),
-					)
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
 
-					Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			It("should allocate an Address from the IPv6 subnet of a dual-stack Pool", func() {
+				pool.Spec.IPv6Subnet = ptr.To(uuid.NewString())
+				Expect(env.Update(context.Background(), &pool)).To(Succeed())
+				Eventually(func(g Gomega) *string {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool),
+					).To(Succeed())
+					return pool.Spec.IPv6Subnet
+				}).ShouldNot(BeNil())
 
-					Expect(
-						env.Get(
-							context.Background(),
-							client.ObjectKeyFromObject(&claim),
-							&ipamv1.IPAddress{},
-						),
-					).
-						To(WithTransform(apierrors.IsNotFound, BeTrue()))
-				},
-			)
-		})
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						*pool.Spec.IPv6Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(
+						uuid.MustParse(*pool.Spec.IPv6Subnet),
+						24,
+						pcclient.WithSubnetIPv6Prefix(64),
+					), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						*pool.Spec.IPv6Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(*pool.Spec.IPv6Subnet),
+						gomock.Any(),
+					).Return(
+						[]netip.Addr{netip.MustParseAddr("fd00::10")}, nil,
+					),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						*pool.Spec.IPv6Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				claim.Annotations = map[string]string{
+					v1alpha1.IPFamilyAnnotation: string(corev1.IPv6Protocol),
+				}
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) ipamv1.IPAddressSpec {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(And(
+					HaveField("Address", "fd00::10"),
+					HaveField("Prefix", HaveValue(BeEquivalentTo(64))),
+				))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It("should fail an IPv6 claim of a Pool without an IPv6 subnet", func() {
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				mockNC.EXPECT().GetSubnet(
+					gomock.Any(),
+					pool.Spec.Subnet,
+					gomock.Any(),
+				).Return(pcclient.NewSubnet(
+					uuid.MustParse(pool.Spec.Subnet),
+					24,
+					pcclient.WithSubnetIPv4Prefix(24),
+				), nil).MinTimes(1)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				claim.Annotations = map[string]string{
+					v1alpha1.IPFamilyAnnotation: string(corev1.IPv6Protocol),
+				}
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+				DeferCleanup(env.CleanupAndWait, context.Background(), &claim)
+
+				Eventually(func(g Gomega) *metav1.Condition {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &claim),
+					).To(Succeed())
+					return conditions.Get(&claim, ipamv1.IPAddressClaimReadyCondition)
+				}).Should(And(
+					HaveField("Status", metav1.ConditionFalse),
+					HaveField("Reason", ipamv1.IPAddressClaimReadyAllocationFailedReason),
+					HaveField("Message", ContainSubstring("has no IPv6 configuration")),
+				))
+
+				Consistently(func(g Gomega) []ipamv1.IPAddress {
+					addresses := ipamv1.IPAddressList{}
+					g.Expect(
+						env.List(context.Background(), &addresses, client.InNamespace(namespace)),
+					).To(Succeed())
+					return addresses.Items
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(HaveLen(0))
+			})
+
+			It("should adopt an IP already reserved for the claim in the IPv6 subnet of a dual-stack Pool", func() {
+				pool.Spec.IPv6Subnet = ptr.To(uuid.NewString())
+				Expect(env.Update(context.Background(), &pool)).To(Succeed())
+				Eventually(func(g Gomega) *string {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool),
+					).To(Succeed())
+					return pool.Spec.IPv6Subnet
+				}).ShouldNot(BeNil())
+
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						*pool.Spec.IPv6Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(
+						uuid.MustParse(*pool.Spec.IPv6Subnet),
+						24,
+						pcclient.WithSubnetIPv6Prefix(64),
+					), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						*pool.Spec.IPv6Subnet,
+						gomock.Cond(func(opts pcclient.ListReservedIPsOpts) bool {
+							return opts.ClientContext != ""
+						}),
+					).Return([]pcclient.ReservedIP{{Address: netip.MustParseAddr("fd00::20")}}, nil),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						*pool.Spec.IPv6Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				claim.Annotations = map[string]string{
+					v1alpha1.IPFamilyAnnotation: string(corev1.IPv6Protocol),
+				}
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) ipamv1.IPAddressSpec {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(And(
+					HaveField("Address", "fd00::20"),
+					HaveField("Prefix", HaveValue(BeEquivalentTo(64))),
+				))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It("should try the next free IP when the IP picked for a restricted Pool is in use", func() {
+				pool.Spec.ExcludedAddresses = []string{"10.0.0.10"}
+				Expect(env.Update(context.Background(), &pool)).To(Succeed())
+				Eventually(func(g Gomega) []string {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool),
+					).To(Succeed())
+					return pool.Spec.ExcludedAddresses
+				}).ShouldNot(BeEmpty())
+
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(
+						uuid.MustParse(pool.Spec.Subnet),
+						24,
+						pcclient.WithSubnetPools(mustIPSet("10.0.0.10-10.0.0.19")),
+					), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return(nil, fmt.Errorf("failed to reserve IP: %w", pcclient.ErrAddressAlreadyReserved)),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return(
+						[]netip.Addr{netip.MustParseAddr("10.0.0.12")}, nil,
+					),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) string {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec.Address
+				}).Should(Equal("10.0.0.12"))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It("should reserve the address requested via the annotation", func() {
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Cond(func(opts pcclient.ListReservedIPsOpts) bool {
+							return opts.ClientContext == ""
+						}),
+					).Return([]pcclient.ReservedIP{{
+						Address:       netip.MustParseAddr("10.0.0.11"),
+						ClientContext: uuid.NewString(),
+					}}, nil),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return(
+						[]netip.Addr{netip.MustParseAddr("10.0.0.10")}, nil,
+					),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				claim.Annotations = map[string]string{
+					v1alpha1.RequestedAddressAnnotation: "10.0.0.10",
+				}
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) string {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec.Address
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(Equal("10.0.0.10"))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It("should fail to allocate a requested address that is already reserved", func() {
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				mockNC.EXPECT().GetSubnet(
+					gomock.Any(),
+					pool.Spec.Subnet,
+					gomock.Any(),
+				).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil).MinTimes(1)
+				mockNC.EXPECT().ListReservedIPs(
+					gomock.Any(),
+					pool.Spec.Subnet,
+					gomock.Any(),
+				).Return([]pcclient.ReservedIP{{
+					Address:       netip.MustParseAddr("10.0.0.10"),
+					ClientContext: uuid.NewString(),
+				}}, nil).MinTimes(1)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				claim.Annotations = map[string]string{
+					v1alpha1.RequestedAddressAnnotation: "10.0.0.10",
+				}
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+				DeferCleanup(env.CleanupAndWait, context.Background(), &claim)
+
+				Eventually(func(g Gomega) *metav1.Condition {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &claim),
+					).To(Succeed())
+					return conditions.Get(&claim, ipamv1.IPAddressClaimReadyCondition)
+				}).Should(And(
+					HaveField("Status", metav1.ConditionFalse),
+					HaveField("Reason", ipamv1.IPAddressClaimReadyAllocationFailedReason),
+					HaveField("Message", ContainSubstring("10.0.0.10 is already reserved")),
+				))
+			})
+
+			It("should adopt an IP already reserved for the claim instead of reserving another", func() {
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Cond(func(opts pcclient.ListReservedIPsOpts) bool {
+							return opts.ClientContext != ""
+						}),
+					).Return([]pcclient.ReservedIP{{Address: netip.MustParseAddr("127.0.0.5")}}, nil),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) string {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec.Address
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(Equal("127.0.0.5"))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It("should release the IPs reserved for the claim besides the adopted one", func() {
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return([]pcclient.ReservedIP{
+						{Address: netip.MustParseAddr("127.0.0.5")},
+						{Address: netip.MustParseAddr("127.0.0.6")},
+					}, nil),
+					mockNC.EXPECT().UnreserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+					).Return([]netip.Addr{netip.MustParseAddr("127.0.0.6")}, nil),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) string {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec.Address
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(Equal("127.0.0.5"))
+				Eventually(claimEventRecorder.Events).Should(Receive(And(
+					HavePrefix(corev1.EventTypeNormal+" "+IPReleasedReason),
+					ContainSubstring("127.0.0.6"),
+				)))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It("should adopt and release the IP reserved with the client context recorded on the claim", func() {
+				// A claim moved with clusterctl move keeps the client context of the claim it was recreated from.
+				const clientContext = "5d9c7a4e-3f0b-4c1e-9a61-2b8f0d7e4c13"
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						pcclient.ListReservedIPsOpts{ClientContext: clientContext},
+					).Return([]pcclient.ReservedIP{{
+						Address:       netip.MustParseAddr("127.0.0.5"),
+						ClientContext: clientContext,
+					}}, nil),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return([]netip.Addr{netip.MustParseAddr("127.0.0.5")}, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				claim.Annotations = map[string]string{v1alpha1.ClientContextAnnotation: clientContext}
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) ipamv1.IPAddress {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address
+				}).Should(And(
+					HaveField("Spec.Address", "127.0.0.5"),
+					HaveField("Annotations", HaveKeyWithValue(v1alpha1.ClientContextAnnotation, clientContext)),
+				))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+				Eventually(claimEventRecorder.Events).Should(Receive(And(
+					HavePrefix(corev1.EventTypeNormal+" "+IPReleasedReason),
+					ContainSubstring("127.0.0.5"),
+				)))
+			})
+
+			It("should reserve the IP with the client context rendered from the Pool template", func() {
+				poolPatch := client.MergeFrom(pool.DeepCopy())
+				pool.Spec.ClientContextTemplate = ptr.To("{{ .Namespace }}/{{ .Name }}/{{ .UID }}")
+				Expect(env.Patch(context.Background(), &pool, poolPatch)).To(Succeed())
+				Eventually(func(g Gomega) *string {
+					cached := v1alpha1.NutanixIPPool{}
+					g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &cached)).To(Succeed())
+					return cached.Spec.ClientContextTemplate
+				}).Should(Equal(pool.Spec.ClientContextTemplate))
+
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Cond(func(opts pcclient.ReserveIPOpts) bool {
+							return strings.HasPrefix(opts.ClientContext, namespace+"/test/")
+						}),
+					).Return(
+						[]netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil,
+					),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return([]netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+				expectedClientContext := namespace + "/test/" + string(claim.UID)
+
+				Eventually(func(g Gomega) ipamv1.IPAddress {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address
+				}).Should(And(
+					HaveField("Spec.Address", "127.0.0.1"),
+					HaveField("Annotations", HaveKeyWithValue(v1alpha1.ClientContextAnnotation, expectedClientContext)),
+				))
+				Eventually(func(g Gomega) map[string]string {
+					g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &claim)).To(Succeed())
+					return claim.Annotations
+				}).Should(HaveKeyWithValue(v1alpha1.ClientContextAnnotation, expectedClientContext))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+				Eventually(claimEventRecorder.Events).Should(Receive(And(
+					HavePrefix(corev1.EventTypeNormal+" "+IPReleasedReason),
+					ContainSubstring("127.0.0.1"),
+				)))
+			})
+
+			It("should fall back to the next subnet of the Pool when the subnet is exhausted", func() {
+				fallbackSubnet := uuid.NewString()
+				pool.Spec.FallbackSubnets = []v1alpha1.NutanixIPPoolFallbackSubnet{{Subnet: fallbackSubnet}}
+				Expect(env.Update(context.Background(), &pool)).To(Succeed())
+				Eventually(func(g Gomega) []v1alpha1.NutanixIPPoolFallbackSubnet {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool),
+					).To(Succeed())
+					return pool.Spec.FallbackSubnets
+				}).Should(HaveLen(1))
+
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						fallbackSubnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(fallbackSubnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						fallbackSubnet,
+						gomock.Any(),
+					).Return(nil, nil),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return(nil, &pcclient.Error{Kind: pcclient.ErrPoolExhausted}),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(fallbackSubnet),
+						gomock.Any(),
+					).Return([]netip.Addr{netip.MustParseAddr("10.0.1.5")}, nil),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						fallbackSubnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) *ipamv1.IPAddress {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return &address
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(And(
+					HaveField("Spec.Address", "10.0.1.5"),
+					HaveField("ObjectMeta.Annotations", HaveKeyWithValue(v1alpha1.SubnetAnnotation, fallbackSubnet)),
+				))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It("should skip a fallback subnet of the Pool that cannot be resolved", func() {
+				fallbackSubnet := uuid.NewString()
+				pool.Spec.FallbackSubnets = []v1alpha1.NutanixIPPoolFallbackSubnet{{Subnet: fallbackSubnet}}
+				Expect(env.Update(context.Background(), &pool)).To(Succeed())
+				Eventually(func(g Gomega) []v1alpha1.NutanixIPPoolFallbackSubnet {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool),
+					).To(Succeed())
+					return pool.Spec.FallbackSubnets
+				}).Should(HaveLen(1))
+
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						fallbackSubnet,
+						gomock.Any(),
+					).Return(nil, &pcclient.Error{Kind: pcclient.ErrSubnetNotFound}),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return([]netip.Addr{netip.MustParseAddr("10.0.0.5")}, nil),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) string {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec.Address
+				}).Should(Equal("10.0.0.5"))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It("should retry a throttled reservation without failing the claim", func() {
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				mockNC.EXPECT().GetSubnet(
+					gomock.Any(),
+					pool.Spec.Subnet,
+					gomock.Any(),
+				).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil).MinTimes(2)
+				mockNC.EXPECT().ListReservedIPs(
+					gomock.Any(),
+					pool.Spec.Subnet,
+					gomock.Any(),
+				).Return(nil, nil).MinTimes(2)
+				gomock.InOrder(
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return(nil, &pcclient.Error{Kind: pcclient.ErrThrottled}),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return([]netip.Addr{netip.MustParseAddr("10.0.0.5")}, nil),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) string {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec.Address
+				}).Should(Equal("10.0.0.5"))
+
+				Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &claim)).To(Succeed())
+				Expect(conditions.Get(&claim, ipamv1.IPAddressClaimReadyCondition)).To(
+					HaveField("Reason", Not(Equal(ipamv1.IPAddressClaimReadyAllocationFailedReason))),
+				)
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It("should not allocate an Address from a Pool that is not ready", func() {
+				conditions.Set(&pool, metav1.Condition{
+					Type:    v1alpha1.NutanixIPPoolReadyCondition,
+					Status:  metav1.ConditionFalse,
+					Reason:  v1alpha1.NutanixIPPoolSubnetResolutionFailedReason,
+					Message: "subnet not found",
+				})
+				Expect(env.Status().Update(context.Background(), &pool)).To(Succeed())
+				Eventually(func(g Gomega) bool {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool),
+					).To(Succeed())
+					return conditions.IsFalse(&pool, v1alpha1.NutanixIPPoolReadyCondition)
+				}).Should(BeTrue())
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+				DeferCleanup(env.CleanupAndWait, context.Background(), &claim)
+
+				Eventually(func(g Gomega) *metav1.Condition {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &claim),
+					).To(Succeed())
+					return conditions.Get(&claim, ipamv1.IPAddressClaimReadyCondition)
+				}).Should(And(
+					HaveField("Status", metav1.ConditionFalse),
+					HaveField("Reason", ipamv1.IPAddressClaimReadyPoolNotReadyReason),
+				))
+
+				Consistently(func(g Gomega) []ipamv1.IPAddress {
+					addresses := ipamv1.IPAddressList{}
+					g.Expect(
+						env.List(context.Background(), &addresses, client.InNamespace(namespace)),
+					).To(Succeed())
+					return addresses.Items
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(HaveLen(0))
+			})
+
+			It("should skip a paused claim until it is unpaused", func() {
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return([]netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				address := ipamv1.IPAddress{}
+				Eventually(func(g Gomega) *metav1.Condition {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &claim),
+					).To(Succeed())
+					return conditions.Get(&claim, clusterv1.PausedCondition)
+				}).Should(And(
+					HaveField("Status", metav1.ConditionFalse),
+					HaveField("Reason", clusterv1.NotPausedReason),
+				))
+
+				// Events of the paused claim are filtered, so its IPAddress is changed to trigger a reconcile.
+				claimPatch := client.MergeFrom(claim.DeepCopy())
+				annotations.AddAnnotations(&claim, map[string]string{clusterv1.PausedAnnotation: ""})
+				Expect(env.Patch(context.Background(), &claim, claimPatch)).To(Succeed())
+				addressPatch := client.MergeFrom(address.DeepCopy())
+				address.Labels = map[string]string{"test": "paused"}
+				Expect(env.Patch(context.Background(), &address, addressPatch)).To(Succeed())
+
+				Eventually(func(g Gomega) *metav1.Condition {
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &claim),
+					).To(Succeed())
+					return conditions.Get(&claim, clusterv1.PausedCondition)
+				}).Should(And(
+					HaveField("Status", metav1.ConditionTrue),
+					HaveField("Reason", clusterv1.PausedReason),
+				))
+
+				Expect(env.Delete(context.Background(), &claim)).To(Succeed())
+				Consistently(claimEventRecorder.Events).WithTimeout(time.Second).ShouldNot(
+					Receive(HavePrefix(corev1.EventTypeNormal + " " + IPReleasedReason)),
+				)
+				Expect(
+					env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &claim),
+				).To(Succeed())
+
+				claimPatch = client.MergeFrom(claim.DeepCopy())
+				delete(claim.Annotations, clusterv1.PausedAnnotation)
+				Expect(env.Patch(context.Background(), &claim, claimPatch)).To(Succeed())
+				Eventually(claimEventRecorder.Events).Should(Receive(And(
+					HavePrefix(corev1.EventTypeNormal+" "+IPReleasedReason),
+					ContainSubstring("127.0.0.1"),
+				)))
+				Eventually(func() error {
+					return env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &claim)
+				}).Should(WithTransform(apierrors.IsNotFound, BeTrue()))
+			})
+
+			It("should not allocate an Address for a claim of a paused Cluster", func() {
+				cluster := clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      clusterName,
+						Namespace: namespace,
+					},
+					Spec: clusterv1.ClusterSpec{
+						Paused: ptr.To(true),
+					},
+				}
+				Expect(env.CreateAndWait(context.Background(), &cluster)).To(Succeed())
+				DeferCleanup(env.CleanupAndWait, context.Background(), &cluster)
+
+				claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+				claim.Spec.ClusterName = clusterName
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				// The mock fails the test on any call to Prism Central.
+				Consistently(func(g Gomega) []ipamv1.IPAddress {
+					addresses := ipamv1.IPAddressList{}
+					g.Expect(
+						env.List(context.Background(), &addresses, client.InNamespace(namespace)),
+					).To(Succeed())
+					return addresses.Items
+				}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(HaveLen(0))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+
+			It(
+				"should retry on errors to and recover to allocate an Address from the Pool",
+				func() {
+					mockNC := mockclient.NewMockNetworkingClient(mockController)
+					mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+					expectedCalls := make([]any, 0, 16)
+					for range 3 {
+						expectedCalls = append(expectedCalls,
+							mockNC.EXPECT().GetSubnet(
+								gomock.Any(),
+								pool.Spec.Subnet,
+								gomock.Any(),
+							).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil).Call,
+							mockNC.EXPECT().ListReservedIPs(
+								gomock.Any(),
+								pool.Spec.Subnet,
+								gomock.Any(),
+							).Return(nil, nil).Call,
+							mockNC.EXPECT().ReserveIPsInSubnet(
+								gomock.Any(),
+								gomock.Any(),
+								subnetWithExtID(pool.Spec.Subnet),
+								gomock.Any(),
+							).Return(nil, errors.New("task failed")).Call,
+						)
+					}
+					expectedCalls = append(expectedCalls,
+						mockNC.EXPECT().GetSubnet(
+							gomock.Any(),
+							pool.Spec.Subnet,
+							gomock.Any(),
+						).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil).Call,
+						mockNC.EXPECT().ListReservedIPs(
+							gomock.Any(),
+							pool.Spec.Subnet,
+							gomock.Any(),
+						).Return(nil, nil).Call,
+						mockNC.EXPECT().ReserveIPsInSubnet(
+							gomock.Any(),
+							gomock.Any(),
+							subnetWithExtID(pool.Spec.Subnet),
+							gomock.Any(),
+						).Return([]netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil).Call,
+					)
+					for range 3 {
+						expectedCalls = append(expectedCalls, mockNC.EXPECT().UnreserveIPs(
+							gomock.Any(),
+							gomock.Any(),
+							pool.Spec.Subnet,
+							gomock.Any(),
+						).Return(nil, errors.New("task failed")).Call)
+					}
+					expectedCalls = append(expectedCalls, mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil).Call)
+					gomock.InOrder(expectedCalls...)
+
+					claim := newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+					expectedIPAddress := ipamv1.IPAddress{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:       "test",
+							Namespace:  namespace,
+							Finalizers: []string{ipamutil.ProtectAddressFinalizer},
+							Annotations: map[string]string{
+								v1alpha1.SubnetAnnotation: pool.Spec.Subnet,
+							},
+							OwnerReferences: []metav1.OwnerReference{{
+								APIVersion:         ipamv1.GroupVersion.String(),
+								BlockOwnerDeletion: ptr.To(true),
+								Controller:         ptr.To(true),
+								Kind:               "IPAddressClaim",
+								Name:               "test",
+							}, {
+								APIVersion:         v1alpha1.GroupVersion.String(),
+								BlockOwnerDeletion: ptr.To(true),
+								Controller:         ptr.To(false),
+								Kind:               v1alpha1.NutanixIPPoolKind,
+								Name:               poolName,
+							}},
+						},
+						Spec: ipamv1.IPAddressSpec{
+							ClaimRef: ipamv1.IPAddressClaimReference{
+								Name: "test",
+							},
+							PoolRef: ipamv1.IPPoolReference{
+								APIGroup: v1alpha1.GroupVersion.Group,
+								Kind:     v1alpha1.NutanixIPPoolKind,
+								Name:     poolName,
+							},
+							Address: "127.0.0.1",
+							Prefix:  ptr.To[int32](24),
+						},
+					}
+
+					Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+					expectedIPAddress.Annotations[v1alpha1.ClientContextAnnotation] = string(claim.UID)
+
+					Eventually(func(g Gomega) *ipamv1.IPAddress {
+						address := ipamv1.IPAddress{}
+						g.Expect(
+							env.Get(
+								context.Background(),
+								client.ObjectKeyFromObject(&claim),
+								&address,
+							),
+						).To(Succeed())
+						return &address
+					}).WithTimeout(time.Second).WithPolling(100 * time.Millisecond).Should(
+						komega.EqualObject(
+							&expectedIPAddress,
+							komega.IgnoreAutogeneratedMetadata,
+							ignoreUIDsOnIPAddress,
+						),
+					)
+
+					Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+
+					Expect(
+						env.Get(
+							context.Background(),
+							client.ObjectKeyFromObject(&claim),
+							&ipamv1.IPAddress{},
+						),
+					).
+						To(WithTransform(apierrors.IsNotFound, BeTrue()))
+				},
+			)
+		})
+
+		When("the referenced global pool exists", func() {
+			var pool v1alpha1.GlobalNutanixIPPool
+
+			BeforeEach(func() {
+				secret := corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-secret",
+						Namespace: namespace,
+					},
+					StringData: map[string]string{
+						credentials.KeyName: `
+		[
+		  {
+		    "type": "basic_auth",
+		    "data": {
+		      "prismCentral":{
+		        "username": "auser",
+		        "password": "apassword"
+		      }
+		    }
+		  }
+		]`,
+					},
+				}
+				Expect(env.CreateAndWait(context.Background(), &secret)).To(Succeed())
+
+				pool = v1alpha1.GlobalNutanixIPPool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "global-" + namespace,
+					},
+					Spec: v1alpha1.NutanixIPPoolSpec{
+						PrismCentral: v1alpha1.PrismCentral{
+							Address: "prism.example.com",
+							Port:    9440,
+							CredentialsSecretRef: v1alpha1.LocalSecretRef{
+								Name:      "test-secret",
+								Namespace: namespace,
+							},
+						},
+						Subnet: uuid.NewString(),
+					},
+				}
+				Expect(env.CreateAndWait(context.Background(), &pool)).To(Succeed())
+				DeferCleanup(env.CleanupAndWait, context.Background(), &pool, &secret)
+			})
+
+			It("should allocate an Address from the Pool in the claim namespace", func() {
+				mockNC := mockclient.NewMockNetworkingClient(mockController)
+				mockPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+				gomock.InOrder(
+					mockNC.EXPECT().GetSubnet(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil),
+					mockNC.EXPECT().ListReservedIPs(
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+					mockNC.EXPECT().ReserveIPsInSubnet(
+						gomock.Any(),
+						gomock.Any(),
+						subnetWithExtID(pool.Spec.Subnet),
+						gomock.Any(),
+					).Return(
+						[]netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil,
+					),
+					mockNC.EXPECT().UnreserveIPs(
+						gomock.Any(),
+						gomock.Any(),
+						pool.Spec.Subnet,
+						gomock.Any(),
+					).Return(nil, nil),
+				)
+
+				claim := newClaim("test", namespace, v1alpha1.GlobalNutanixIPPoolKind, pool.Name)
+				Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+
+				Eventually(func(g Gomega) ipamv1.IPAddressSpec {
+					address := ipamv1.IPAddress{}
+					g.Expect(
+						env.Get(context.Background(), client.ObjectKeyFromObject(&claim), &address),
+					).To(Succeed())
+					return address.Spec
+				}).Should(And(
+					HaveField("Address", "127.0.0.1"),
+					HaveField("PoolRef", ipamv1.IPPoolReference{
+						APIGroup: v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.GlobalNutanixIPPoolKind,
+						Name:     pool.Name,
+					}),
+				))
+
+				Expect(env.CleanupAndWait(context.Background(), &claim)).To(Succeed())
+			})
+		})
+	})
+})
+
+// subnetWithExtID matches a resolved subnet by its extID.
+func subnetWithExtID(extID string) gomock.Matcher {
+	return gomock.Cond(func(subnet *pcclient.Subnet) bool {
+		return subnet.ExtID().String() == extID
+	})
+}
+
+var _ = DescribeTable("addressToReserve",
+	func(
+		requested string,
+		allowed, excluded, reserved *netipx.IPSet,
+		subnetPools *netipx.IPSet,
+		want string,
+		wantErr string,
+	) {
+		var opts []pcclient.SubnetOption
+		if subnetPools != nil {
+			opts = append(opts, pcclient.WithSubnetPools(subnetPools))
+		}
+		subnet := pcclient.NewSubnet(uuid.New(), 24, opts...)
+
+		var requestedAddress netip.Addr
+		if requested != "" {
+			requestedAddress = netip.MustParseAddr(requested)
+		}
+		if reserved == nil {
+			reserved = mustIPSet()
+		}
+
+		addr, err := addressToReserve(subnet, "test-subnet", requestedAddress, allowed, excluded, reserved)
+		if wantErr != "" {
+			Expect(err).To(MatchError(ContainSubstring(wantErr)))
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		if want == "" {
+			Expect(addr.IsValid()).To(BeFalse())
+			return
+		}
+		Expect(addr.String()).To(Equal(want))
+	},
+	Entry("any free IP without restrictions",
+		"", nil, nil, nil, mustIPSet("10.0.0.10-10.0.0.19"), "", ""),
+	Entry("the requested IP",
+		"10.0.0.15", nil, nil, nil, mustIPSet("10.0.0.10-10.0.0.19"), "10.0.0.15", ""),
+	Entry("the lowest free allowed IP that is neither excluded nor reserved",
+		"",
+		mustIPSet("10.0.0.12-10.0.0.15"),
+		mustIPSet("10.0.0.12-10.0.0.12"),
+		mustIPSet("10.0.0.13-10.0.0.13"),
+		mustIPSet("10.0.0.10-10.0.0.19"),
+		"10.0.0.14", ""),
+	Entry("the lowest free IP of the subnet pools that is not excluded",
+		"", nil, mustIPSet("10.0.0.10-10.0.0.11"), nil, mustIPSet("10.0.0.10-10.0.0.19"), "10.0.0.12", ""),
+	Entry("no IP if all allowed IPs are reserved",
+		"",
+		mustIPSet("10.0.0.12-10.0.0.13"),
+		nil,
+		mustIPSet("10.0.0.12-10.0.0.13"),
+		mustIPSet("10.0.0.10-10.0.0.19"),
+		"", "no free IP"),
+	Entry("no IP if the subnet has no IP pools",
+		"", mustIPSet("10.0.0.12-10.0.0.13"), nil, nil, nil, "", "has no IP pools"),
+	Entry("no requested IP that is already reserved",
+		"10.0.0.15", nil, nil, mustIPSet("10.0.0.15-10.0.0.15"), nil, "", "already reserved"),
+	Entry("no requested IP outside of the allowed IPs",
+		"10.0.0.15", mustIPSet("10.0.0.10-10.0.0.11"), nil, nil, nil, "", "not within the allowedRanges"),
+	Entry("no requested IP that is excluded",
+		"10.0.0.15", nil, mustIPSet("10.0.0.15-10.0.0.15"), nil, nil, "", "in the excludedAddresses"),
+)
+
+var _ = Describe("allocationError", func() {
+	It("should return a terminal error for a requested IP already reserved by others", func() {
+		subnet := pcclient.NewSubnet(uuid.New(), 24, pcclient.WithSubnetPools(mustIPSet("10.0.0.10-10.0.0.19")))
+		_, err := addressToReserve(
+			subnet, "test-subnet", netip.MustParseAddr("10.0.0.15"), nil, nil, mustIPSet("10.0.0.15-10.0.0.15"),
+		)
+		Expect(err).To(MatchError(pcclient.ErrAddressAlreadyReserved))
+
+		h := &IPAddressClaimHandler{claim: &ipamv1.IPAddressClaim{}}
+		err = h.allocationError(err)
+		Expect(errors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+		Expect(conditions.Get(h.claim, ipamv1.IPAddressClaimReadyCondition)).To(
+			HaveField("Reason", ipamv1.IPAddressClaimReadyAllocationFailedReason),
+		)
 	})
 })
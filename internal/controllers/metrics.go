@@ -0,0 +1,55 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	poolMetricLabels = []string{"kind", "pool"}
+
+	poolAllocatedAddresses = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "caipamx_pool_allocated_addresses",
+			Help: "Number of IPAddresses allocated from the pool.",
+		},
+		poolMetricLabels,
+	)
+
+	poolFreeAddresses = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "caipamx_pool_free_addresses",
+			Help: "Number of free IPs in the subnets of the pool, as reported by Prism Central.",
+		},
+		poolMetricLabels,
+	)
+)
+
+func init() { //nolint:gochecknoinits // Metrics must be registered before the metrics server is started.
+	metrics.Registry.MustRegister(poolAllocatedAddresses, poolFreeAddresses)
+}
+
+// poolMetricLabel returns the value of the pool label of the metrics recorded for the pool.
+func poolMetricLabel(pool ctrlclient.Object) string {
+	return ctrlclient.ObjectKeyFromObject(pool).String()
+}
+
+// recordPoolAddressMetrics updates the address gauges of the pool from its status.
+func recordPoolAddressMetrics(pool genericNutanixIPPool, kind string) {
+	addresses := pool.PoolStatus().Addresses
+	if addresses == nil {
+		return
+	}
+	poolAllocatedAddresses.WithLabelValues(kind, poolMetricLabel(pool)).Set(float64(addresses.Reserved))
+	poolFreeAddresses.WithLabelValues(kind, poolMetricLabel(pool)).Set(float64(addresses.Free))
+}
+
+// deletePoolAddressMetrics removes the address gauges of a deleted pool.
+func deletePoolAddressMetrics(pool genericNutanixIPPool, kind string) {
+	poolAllocatedAddresses.DeleteLabelValues(kind, poolMetricLabel(pool))
+	poolFreeAddresses.DeleteLabelValues(kind, poolMetricLabel(pool))
+}
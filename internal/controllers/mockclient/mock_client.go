@@ -248,6 +248,45 @@ func (c *MockNetworkingClientGetSubnetCall) DoAndReturn(f func(context.Context,
 	return c
 }
 
+// ListReservedIPs mocks base method.
+func (m *MockNetworkingClient) ListReservedIPs(ctx context.Context, subnet string, opts client.ListReservedIPsOpts) ([]client.ReservedIP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReservedIPs", ctx, subnet, opts)
+	ret0, _ := ret[0].([]client.ReservedIP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReservedIPs indicates an expected call of ListReservedIPs.
+func (mr *MockNetworkingClientMockRecorder) ListReservedIPs(ctx, subnet, opts any) *MockNetworkingClientListReservedIPsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReservedIPs", reflect.TypeOf((*MockNetworkingClient)(nil).ListReservedIPs), ctx, subnet, opts)
+	return &MockNetworkingClientListReservedIPsCall{Call: call}
+}
+
+// MockNetworkingClientListReservedIPsCall wrap *gomock.Call
+type MockNetworkingClientListReservedIPsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockNetworkingClientListReservedIPsCall) Return(arg0 []client.ReservedIP, arg1 error) *MockNetworkingClientListReservedIPsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockNetworkingClientListReservedIPsCall) Do(f func(context.Context, string, client.ListReservedIPsOpts) ([]client.ReservedIP, error)) *MockNetworkingClientListReservedIPsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockNetworkingClientListReservedIPsCall) DoAndReturn(f func(context.Context, string, client.ListReservedIPsOpts) ([]client.ReservedIP, error)) *MockNetworkingClientListReservedIPsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ReserveIPs mocks base method.
 func (m *MockNetworkingClient) ReserveIPs(ctx context.Context, reserveType client.IPReservationTypeFunc, subnet string, opts client.ReserveIPOpts) ([]netip.Addr, error) {
 	m.ctrl.T.Helper()
@@ -287,6 +326,45 @@ func (c *MockNetworkingClientReserveIPsCall) DoAndReturn(f func(context.Context,
 	return c
 }
 
+// ReserveIPsInSubnet mocks base method.
+func (m *MockNetworkingClient) ReserveIPsInSubnet(ctx context.Context, reserveType client.IPReservationTypeFunc, subnet *client.Subnet, opts client.ReserveIPOpts) ([]netip.Addr, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIPsInSubnet", ctx, reserveType, subnet, opts)
+	ret0, _ := ret[0].([]netip.Addr)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIPsInSubnet indicates an expected call of ReserveIPsInSubnet.
+func (mr *MockNetworkingClientMockRecorder) ReserveIPsInSubnet(ctx, reserveType, subnet, opts any) *MockNetworkingClientReserveIPsInSubnetCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIPsInSubnet", reflect.TypeOf((*MockNetworkingClient)(nil).ReserveIPsInSubnet), ctx, reserveType, subnet, opts)
+	return &MockNetworkingClientReserveIPsInSubnetCall{Call: call}
+}
+
+// MockNetworkingClientReserveIPsInSubnetCall wrap *gomock.Call
+type MockNetworkingClientReserveIPsInSubnetCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockNetworkingClientReserveIPsInSubnetCall) Return(arg0 []netip.Addr, arg1 error) *MockNetworkingClientReserveIPsInSubnetCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockNetworkingClientReserveIPsInSubnetCall) Do(f func(context.Context, client.IPReservationTypeFunc, *client.Subnet, client.ReserveIPOpts) ([]netip.Addr, error)) *MockNetworkingClientReserveIPsInSubnetCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockNetworkingClientReserveIPsInSubnetCall) DoAndReturn(f func(context.Context, client.IPReservationTypeFunc, *client.Subnet, client.ReserveIPOpts) ([]netip.Addr, error)) *MockNetworkingClientReserveIPsInSubnetCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // UnreserveIPs mocks base method.
 func (m *MockNetworkingClient) UnreserveIPs(ctx context.Context, unreserveType client.IPUnreservationTypeFunc, subnet string, opts client.UnreserveIPOpts) ([]netip.Addr, error) {
 	m.ctrl.T.Helper()
@@ -325,3 +403,42 @@ func (c *MockNetworkingClientUnreserveIPsCall) DoAndReturn(f func(context.Contex
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
+
+// UnreserveIPsInSubnet mocks base method.
+func (m *MockNetworkingClient) UnreserveIPsInSubnet(ctx context.Context, unreserveType client.IPUnreservationTypeFunc, subnet *client.Subnet) ([]netip.Addr, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnreserveIPsInSubnet", ctx, unreserveType, subnet)
+	ret0, _ := ret[0].([]netip.Addr)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnreserveIPsInSubnet indicates an expected call of UnreserveIPsInSubnet.
+func (mr *MockNetworkingClientMockRecorder) UnreserveIPsInSubnet(ctx, unreserveType, subnet any) *MockNetworkingClientUnreserveIPsInSubnetCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnreserveIPsInSubnet", reflect.TypeOf((*MockNetworkingClient)(nil).UnreserveIPsInSubnet), ctx, unreserveType, subnet)
+	return &MockNetworkingClientUnreserveIPsInSubnetCall{Call: call}
+}
+
+// MockNetworkingClientUnreserveIPsInSubnetCall wrap *gomock.Call
+type MockNetworkingClientUnreserveIPsInSubnetCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockNetworkingClientUnreserveIPsInSubnetCall) Return(arg0 []netip.Addr, arg1 error) *MockNetworkingClientUnreserveIPsInSubnetCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockNetworkingClientUnreserveIPsInSubnetCall) Do(f func(context.Context, client.IPUnreservationTypeFunc, *client.Subnet) ([]netip.Addr, error)) *MockNetworkingClientUnreserveIPsInSubnetCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockNetworkingClientUnreserveIPsInSubnetCall) DoAndReturn(f func(context.Context, client.IPUnreservationTypeFunc, *client.Subnet) ([]netip.Addr, error)) *MockNetworkingClientUnreserveIPsInSubnetCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -4,25 +4,38 @@
 package controllers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/nutanix-cloud-native/prism-go-client/environment"
 	"github.com/nutanix-cloud-native/prism-go-client/environment/credentials"
 	"github.com/nutanix-cloud-native/prism-go-client/environment/providers/kubernetes"
-	"github.com/nutanix-cloud-native/prism-go-client/environment/types"
+	envtypes "github.com/nutanix-cloud-native/prism-go-client/environment/types"
 
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
 	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/client"
 )
 
 type clientCacheParams struct {
-	managementEndpoint types.ManagementEndpoint
+	managementEndpoint envtypes.ManagementEndpoint
 	key                string
+	version            string
 }
 
-var _ client.CachedClientParams = &clientCacheParams{}
+var _ client.VersionedClientParams = &clientCacheParams{}
 
 func newClientCacheParams(
 	prismEndpoint credentials.NutanixPrismEndpoint,
@@ -41,16 +54,207 @@ func newClientCacheParams(
 		return nil, fmt.Errorf("failed to get management endpoint: %w", err)
 	}
 
+	version, err := credentialsVersion(prismEndpoint, secretInformer, cmInformer)
+	if err != nil {
+		return nil, err
+	}
+
 	return &clientCacheParams{
 		key:                ctrlclient.ObjectKeyFromObject(pool).String(),
 		managementEndpoint: *me,
+		version:            version,
 	}, nil
 }
 
-func (p *clientCacheParams) ManagementEndpoint() types.ManagementEndpoint {
+// credentialsVersion returns a digest of the resourceVersion of the credentials secret and of the trust bundle
+// content of a Prism Central endpoint, which changes whenever either is rotated.
+func credentialsVersion(
+	prismEndpoint credentials.NutanixPrismEndpoint,
+	secretInformer coreinformers.SecretInformer,
+	cmInformer coreinformers.ConfigMapInformer,
+) (string, error) {
+	digest := sha256.New()
+
+	if ref := prismEndpoint.CredentialRef; ref != nil {
+		secret, err := secretInformer.Lister().Secrets(ref.Namespace).Get(ref.Name)
+		if err != nil {
+			return "", fmt.Errorf("failed to get credentials secret: %w", err)
+		}
+		fmt.Fprintf(digest, "secret=%s\n", secret.ResourceVersion)
+	}
+
+	if bundle := prismEndpoint.AdditionalTrustBundle; bundle != nil {
+		switch bundle.Kind {
+		case credentials.NutanixTrustBundleKindString:
+			fmt.Fprintf(digest, "trustBundle=%q\n", bundle.Data)
+		case credentials.NutanixTrustBundleKindConfigMap:
+			cm, err := cmInformer.Lister().ConfigMaps(bundle.Namespace).Get(bundle.Name)
+			if err != nil {
+				return "", fmt.Errorf("failed to get trust bundle configmap: %w", err)
+			}
+			for _, key := range slices.Sorted(maps.Keys(cm.Data)) {
+				fmt.Fprintf(digest, "trustBundle[%q]=%q\n", key, cm.Data[key])
+			}
+			for _, key := range slices.Sorted(maps.Keys(cm.BinaryData)) {
+				fmt.Fprintf(digest, "trustBundle[%q]=%q\n", key, cm.BinaryData[key])
+			}
+		}
+	}
+
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// getClientForPool returns a Prism Central client configured from the pool spec.
+func getClientForPool(
+	pool genericNutanixIPPool,
+	pcClientGetter func(client.CachedClientParams) (client.Client, error),
+	secretInformer coreinformers.SecretInformer,
+	cmInformer coreinformers.ConfigMapInformer,
+) (client.Client, error) {
+	pc := pool.PoolSpec().PrismCentral
+
+	var additionalTrustBundle *credentials.NutanixTrustBundleReference
+	if pc.AdditionalTrustBundle != nil {
+		switch {
+		case len(pc.AdditionalTrustBundle.Data) > 0:
+			additionalTrustBundle = &credentials.NutanixTrustBundleReference{
+				Data: string(pc.AdditionalTrustBundle.Data),
+				Kind: credentials.NutanixTrustBundleKindString,
+			}
+		case pc.AdditionalTrustBundle.ConfigMapReference != nil && pc.AdditionalTrustBundle.ConfigMapReference.Name != "":
+			additionalTrustBundle = &credentials.NutanixTrustBundleReference{
+				Name:      pc.AdditionalTrustBundle.ConfigMapReference.Name,
+				Namespace: v1alpha1.ReferenceNamespace(pool, pc.AdditionalTrustBundle.ConfigMapReference.Namespace),
+				Kind:      credentials.NutanixTrustBundleKindConfigMap,
+			}
+		default:
+			return nil, fmt.Errorf(
+				"invalid additional trust bundle configuration: either data or secretRef must be set",
+			)
+		}
+	}
+
+	cacheClientParams, err := newClientCacheParams(
+		credentials.NutanixPrismEndpoint{
+			Address:               pc.Address,
+			Port:                  int32(pc.Port),
+			Insecure:              pc.Insecure,
+			AdditionalTrustBundle: additionalTrustBundle,
+			CredentialRef: &credentials.NutanixCredentialReference{
+				Kind:      credentials.SecretKind,
+				Name:      pc.CredentialsSecretRef.Name,
+				Namespace: v1alpha1.ReferenceNamespace(pool, pc.CredentialsSecretRef.Namespace),
+			},
+		},
+		secretInformer,
+		cmInformer,
+		pool,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Nutanix cache client params: %w", err)
+	}
+
+	c, err := pcClientGetter(cacheClientParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Nutanix client: %w", err)
+	}
+
+	return c, nil
+}
+
+// prismCentralEndpoint returns the host:port of the Prism Central of a pool.
+func prismCentralEndpoint(pc v1alpha1.PrismCentral) string {
+	return net.JoinHostPort(pc.Address, strconv.Itoa(int(pc.Port)))
+}
+
+func (p *clientCacheParams) ManagementEndpoint() envtypes.ManagementEndpoint {
 	return p.managementEndpoint
 }
 
 func (p *clientCacheParams) Key() string {
 	return p.key
 }
+
+func (p *clientCacheParams) Version() string {
+	return p.version
+}
+
+// referencesSecret returns whether the pool references the secret as its Prism Central credentials.
+func referencesSecret(pool genericNutanixIPPool, secret ctrlclient.Object) bool {
+	ref := pool.PoolSpec().PrismCentral.CredentialsSecretRef
+	return ref.Name == secret.GetName() && v1alpha1.ReferenceNamespace(pool, ref.Namespace) == secret.GetNamespace()
+}
+
+// referencesConfigMap returns whether the pool references the configmap as its additional trust bundle.
+func referencesConfigMap(pool genericNutanixIPPool, cm ctrlclient.Object) bool {
+	bundle := pool.PoolSpec().PrismCentral.AdditionalTrustBundle
+	if bundle == nil || bundle.ConfigMapReference == nil {
+		return false
+	}
+	ref := bundle.ConfigMapReference
+	return ref.Name == cm.GetName() && v1alpha1.ReferenceNamespace(pool, ref.Namespace) == cm.GetNamespace()
+}
+
+// credentialsToPools maps a secret or configmap to the pools of the given kind that reference it as their Prism
+// Central credentials or trust bundle. The cached clients of these pools are evicted, so that the pools are
+// reconciled with a client using the rotated credentials or trust bundle.
+func credentialsToPools(
+	k8sClient ctrlclient.Client,
+	kind string,
+) func(context.Context, ctrlclient.Object) []reconcile.Request {
+	return func(ctx context.Context, o ctrlclient.Object) []reconcile.Request {
+		var references func(genericNutanixIPPool, ctrlclient.Object) bool
+		switch o.(type) {
+		case *corev1.Secret:
+			references = referencesSecret
+		case *corev1.ConfigMap:
+			references = referencesConfigMap
+		default:
+			return nil
+		}
+
+		pools, err := listPools(ctx, k8sClient, kind)
+		if err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to list pools referencing credentials",
+				"kind", kind, "object", ctrlclient.ObjectKeyFromObject(o))
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, pool := range pools {
+			if !references(pool, o) {
+				continue
+			}
+			key := types.NamespacedName{Namespace: pool.GetNamespace(), Name: pool.GetName()}
+			client.EvictClient(key.String())
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+		return requests
+	}
+}
+
+// listPools lists all pools of the given kind.
+func listPools(ctx context.Context, k8sClient ctrlclient.Client, kind string) ([]genericNutanixIPPool, error) {
+	var pools []genericNutanixIPPool
+	switch kind {
+	case v1alpha1.NutanixIPPoolKind:
+		list := &v1alpha1.NutanixIPPoolList{}
+		if err := k8sClient.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			pools = append(pools, &list.Items[i])
+		}
+	case v1alpha1.GlobalNutanixIPPoolKind:
+		list := &v1alpha1.GlobalNutanixIPPoolList{}
+		if err := k8sClient.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			pools = append(pools, &list.Items[i])
+		}
+	default:
+		return nil, fmt.Errorf("unknown pool kind %q", kind)
+	}
+	return pools, nil
+}
@@ -0,0 +1,812 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/utils/ptr"
+	ipamv1 "sigs.k8s.io/cluster-api/api/ipam/v1beta2"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
+	pcclient "github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/client"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/index"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/poolutil"
+)
+
+// poolReconciler holds the shared configuration and logic of the NutanixIPPool and GlobalNutanixIPPool
+// reconcilers.
+type poolReconciler struct {
+	client           ctrlclient.Client
+	watchFilterValue string
+	pcClientGetter   func(pcclient.CachedClientParams) (pcclient.Client, error)
+	secretInformer   coreinformers.SecretInformer
+	cmInformer       coreinformers.ConfigMapInformer
+	opts             reconcilerOptions
+	subnetRefreshes  *subnetRefreshes
+}
+
+func newPoolReconciler(
+	client ctrlclient.Client,
+	watchFilter string,
+	secretInformer coreinformers.SecretInformer,
+	cmInformer coreinformers.ConfigMapInformer,
+	opts reconcilerOptions,
+) poolReconciler {
+	return poolReconciler{
+		client:           client,
+		pcClientGetter:   pcclient.GetClient,
+		watchFilterValue: watchFilter,
+		secretInformer:   secretInformer,
+		cmInformer:       cmInformer,
+		opts:             opts,
+		subnetRefreshes:  newSubnetRefreshes(),
+	}
+}
+
+// subnetRefreshes tracks when the subnets of each pool were last refreshed from Prism Central, so that the pool
+// reconciles triggered by IPAddress changes use the cached subnets instead of getting them from Prism Central.
+type subnetRefreshes struct {
+	mu   sync.Mutex
+	last map[types.UID]subnetRefresh
+}
+
+type subnetRefresh struct {
+	generation int64
+	time       time.Time
+}
+
+func newSubnetRefreshes() *subnetRefreshes {
+	return &subnetRefreshes{last: map[types.UID]subnetRefresh{}}
+}
+
+// due returns whether the subnets of the pool must be refreshed from Prism Central: if they were never refreshed,
+// if the pool spec changed or if they were last refreshed at least one sync period ago, as on the periodic requeue.
+func (s *subnetRefreshes) due(pool genericNutanixIPPool, syncPeriod time.Duration, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.last[pool.GetUID()]
+	return !ok || last.generation != pool.GetGeneration() || now.Sub(last.time) >= syncPeriod
+}
+
+// done records that the subnets of the pool were refreshed from Prism Central.
+func (s *subnetRefreshes) done(pool genericNutanixIPPool, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last[pool.GetUID()] = subnetRefresh{generation: pool.GetGeneration(), time: now}
+}
+
+// forget drops the refresh of a deleted pool.
+func (s *subnetRefreshes) forget(pool genericNutanixIPPool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.last, pool.GetUID())
+}
+
+func (r *poolReconciler) setupWithManager(
+	ctx context.Context,
+	mgr ctrl.Manager,
+	pool genericNutanixIPPool,
+	kind string,
+	reconciler reconcile.Reconciler,
+) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(pool).
+		Watches(
+			&ipamv1.IPAddress{},
+			handler.EnqueueRequestsFromMapFunc(ipAddressToPool(kind)),
+		).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.opts.maxConcurrentReconciles,
+		}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(
+			mgr.GetScheme(), ctrl.LoggerFrom(ctx), r.watchFilterValue,
+		))
+
+	// Reconcile the pools referencing a secret or configmap when it changes, so that rotated credentials and
+	// trust bundles are picked up without restarting the controller. Resyncs of the informers are ignored.
+	credentialsHandler := handler.EnqueueRequestsFromMapFunc(credentialsToPools(r.client, kind))
+	if r.secretInformer != nil {
+		b = b.WatchesRawSource(&source.Informer{
+			Informer:   r.secretInformer.Informer(),
+			Handler:    credentialsHandler,
+			Predicates: []predicate.Predicate{predicate.ResourceVersionChangedPredicate{}},
+		})
+	}
+	if r.cmInformer != nil {
+		b = b.WatchesRawSource(&source.Informer{
+			Informer:   r.cmInformer.Informer(),
+			Handler:    credentialsHandler,
+			Predicates: []predicate.Predicate{predicate.ResourceVersionChangedPredicate{}},
+		})
+	}
+
+	return b.Complete(reconciler)
+}
+
+// NutanixIPPoolReconciler reconciles a NutanixIPPool object, populating its status with the capacity and usage
+// of the referenced subnet, and with conditions reporting whether the pool can be used to allocate IPs.
+type NutanixIPPoolReconciler struct {
+	poolReconciler
+}
+
+func NewNutanixIPPoolReconciler(
+	client ctrlclient.Client,
+	watchFilter string,
+	secretInformer coreinformers.SecretInformer,
+	cmInformer coreinformers.ConfigMapInformer,
+	opts reconcilerOptions,
+) *NutanixIPPoolReconciler {
+	return &NutanixIPPoolReconciler{
+		poolReconciler: newPoolReconciler(client, watchFilter, secretInformer, cmInformer, opts),
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NutanixIPPoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	return r.setupWithManager(ctx, mgr, &v1alpha1.NutanixIPPool{}, v1alpha1.NutanixIPPoolKind, r)
+}
+
+// GlobalNutanixIPPoolReconciler reconciles a GlobalNutanixIPPool object, populating its status with the
+// capacity and usage of the referenced subnet, and with conditions reporting whether the pool can be used to
+// allocate IPs.
+type GlobalNutanixIPPoolReconciler struct {
+	poolReconciler
+}
+
+func NewGlobalNutanixIPPoolReconciler(
+	client ctrlclient.Client,
+	watchFilter string,
+	secretInformer coreinformers.SecretInformer,
+	cmInformer coreinformers.ConfigMapInformer,
+	opts reconcilerOptions,
+) *GlobalNutanixIPPoolReconciler {
+	return &GlobalNutanixIPPoolReconciler{
+		poolReconciler: newPoolReconciler(client, watchFilter, secretInformer, cmInformer, opts),
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GlobalNutanixIPPoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	return r.setupWithManager(ctx, mgr, &v1alpha1.GlobalNutanixIPPool{}, v1alpha1.GlobalNutanixIPPoolKind, r)
+}
+
+// ipAddressToPool maps an IPAddress to the pool of the given kind that it was allocated from.
+func ipAddressToPool(kind string) func(context.Context, ctrlclient.Object) []reconcile.Request {
+	return func(_ context.Context, o ctrlclient.Object) []reconcile.Request {
+		ipAddress, ok := o.(*ipamv1.IPAddress)
+		if !ok {
+			return nil
+		}
+
+		if ipAddress.Spec.PoolRef.APIGroup != v1alpha1.GroupVersion.Group ||
+			ipAddress.Spec.PoolRef.Kind != kind {
+			return nil
+		}
+
+		// A GlobalNutanixIPPool is cluster scoped, so it has no namespace.
+		poolNamespace := ipAddress.Namespace
+		if kind == v1alpha1.GlobalNutanixIPPoolKind {
+			poolNamespace = ""
+		}
+
+		return []reconcile.Request{{
+			NamespacedName: types.NamespacedName{
+				Namespace: poolNamespace,
+				Name:      ipAddress.Spec.PoolRef.Name,
+			},
+		}}
+	}
+}
+
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=nutanixippools;globalnutanixippools,verbs=update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=nutanixippools/status;globalnutanixippools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=patch
+
+// Reconcile reconciles a NutanixIPPool object.
+func (r *NutanixIPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pool := &v1alpha1.NutanixIPPool{}
+	if err := r.client.Get(ctx, req.NamespacedName, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch NutanixIPPool: %w", err)
+	}
+
+	return r.reconcile(ctx, pool, v1alpha1.NutanixIPPoolKind)
+}
+
+// Reconcile reconciles a GlobalNutanixIPPool object.
+func (r *GlobalNutanixIPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pool := &v1alpha1.GlobalNutanixIPPool{}
+	if err := r.client.Get(ctx, req.NamespacedName, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch GlobalNutanixIPPool: %w", err)
+	}
+
+	return r.reconcile(ctx, pool, v1alpha1.GlobalNutanixIPPoolKind)
+}
+
+func (r *poolReconciler) reconcile(
+	ctx context.Context,
+	pool genericNutanixIPPool,
+	kind string,
+) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if annotations.HasPaused(pool) {
+		log.Info("Reconciliation is paused for this pool")
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(pool, r.client)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to create patch helper: %w", err)
+	}
+
+	if !pool.GetDeletionTimestamp().IsZero() {
+		defer func() {
+			if err := patchHelper.Patch(ctx, pool); err != nil {
+				reterr = kerrors.NewAggregate([]error{reterr, err})
+			}
+		}()
+
+		return ctrl.Result{}, r.reconcileDelete(ctx, pool, kind)
+	}
+
+	defer func() {
+		if err := setPoolReadyCondition(pool); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+		if err := patchHelper.Patch(ctx, pool); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	// Protect the pool from deletion while IPs are allocated from it, as the pool is required to release them.
+	controllerutil.AddFinalizer(pool, v1alpha1.ProtectPoolFinalizer)
+	conditions.Set(pool, metav1.Condition{
+		Type:   v1alpha1.NutanixIPPoolDeletingCondition,
+		Status: metav1.ConditionFalse,
+		Reason: v1alpha1.NutanixIPPoolNotDeletingReason,
+	})
+
+	if err := r.ensureMoveLabels(ctx, pool); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileStatus(ctx, pool, kind); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Prism Central does not notify us of changes to the subnet usage made outside of this provider, so
+	// requeue to keep the status up to date.
+	return ctrl.Result{RequeueAfter: r.opts.poolSyncPeriod}, nil
+}
+
+// reconcileDelete removes the pool finalizer once no IPAddresses reference the pool, reporting the blocking
+// IPAddresses in the Deleting condition otherwise. The pool is requeued by the IPAddress watch when the
+// IPAddresses are deleted.
+func (r *poolReconciler) reconcileDelete(
+	ctx context.Context,
+	pool genericNutanixIPPool,
+	kind string,
+) error {
+	addresses, err := r.poolAddresses(ctx, pool, kind)
+	if err != nil {
+		return err
+	}
+
+	if len(addresses) == 0 {
+		controllerutil.RemoveFinalizer(pool, v1alpha1.ProtectPoolFinalizer)
+		deletePoolAddressMetrics(pool, kind)
+		r.subnetRefreshes.forget(pool)
+		return nil
+	}
+
+	names := make([]string, 0, len(addresses))
+	for i := range addresses {
+		names = append(names, ctrlclient.ObjectKeyFromObject(&addresses[i]).String())
+	}
+	slices.Sort(names)
+	const maxListedAddresses = 10
+	message := fmt.Sprintf(
+		"Waiting for IPAddresses to be deleted: %s",
+		strings.Join(names[:min(len(names), maxListedAddresses)], ", "),
+	)
+	if len(names) > maxListedAddresses {
+		message += fmt.Sprintf(" and %d more", len(names)-maxListedAddresses)
+	}
+
+	conditions.Set(pool, metav1.Condition{
+		Type:    v1alpha1.NutanixIPPoolDeletingCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.NutanixIPPoolDeletingWaitingForIPAddressesReason,
+		Message: message,
+	})
+
+	return nil
+}
+
+// ensureMoveLabels labels the credentials secret and the trust bundle configmap referenced by the pool with the
+// clusterctl move label, so that clusterctl move moves them to the target management cluster along with the pool.
+// References that cannot be found are skipped, as they are reported by the CredentialsValid condition.
+func (r *poolReconciler) ensureMoveLabels(ctx context.Context, pool genericNutanixIPPool) error {
+	pc := pool.PoolSpec().PrismCentral
+
+	if r.secretInformer != nil {
+		secret, err := r.secretInformer.Lister().
+			Secrets(v1alpha1.ReferenceNamespace(pool, pc.CredentialsSecretRef.Namespace)).
+			Get(pc.CredentialsSecretRef.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return fmt.Errorf("failed to get credentials secret: %w", err)
+		default:
+			if err := r.ensureMoveLabel(ctx, "Secret", secret.DeepCopy()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.cmInformer != nil && pc.AdditionalTrustBundle != nil && pc.AdditionalTrustBundle.ConfigMapReference != nil {
+		ref := pc.AdditionalTrustBundle.ConfigMapReference
+		configMap, err := r.cmInformer.Lister().
+			ConfigMaps(v1alpha1.ReferenceNamespace(pool, ref.Namespace)).
+			Get(ref.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return fmt.Errorf("failed to get trust bundle configmap: %w", err)
+		default:
+			if err := r.ensureMoveLabel(ctx, "ConfigMap", configMap.DeepCopy()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureMoveLabel adds the clusterctl move label to the object if it is missing.
+func (r *poolReconciler) ensureMoveLabel(ctx context.Context, kind string, obj ctrlclient.Object) error {
+	if _, ok := obj.GetLabels()[clusterctlv1.ClusterctlMoveLabel]; ok {
+		return nil
+	}
+
+	patch := ctrlclient.MergeFrom(obj.DeepCopyObject().(ctrlclient.Object))
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[clusterctlv1.ClusterctlMoveLabel] = ""
+	obj.SetLabels(labels)
+	if err := r.client.Patch(ctx, obj, patch); err != nil {
+		return fmt.Errorf(
+			"failed to add %s label to %s %s: %w",
+			clusterctlv1.ClusterctlMoveLabel, kind, ctrlclient.ObjectKeyFromObject(obj), err,
+		)
+	}
+	return nil
+}
+
+// poolAddresses returns the IPAddresses allocated from the pool.
+func (r *poolReconciler) poolAddresses(
+	ctx context.Context,
+	pool genericNutanixIPPool,
+	kind string,
+) ([]ipamv1.IPAddress, error) {
+	addresses := &ipamv1.IPAddressList{}
+	if err := r.client.List(ctx, addresses,
+		ctrlclient.InNamespace(pool.GetNamespace()),
+		ctrlclient.MatchingFields{
+			index.IPAddressPoolRefCombinedField: index.IPPoolRefValue(ipamv1.IPPoolReference{
+				Name:     pool.GetName(),
+				Kind:     kind,
+				APIGroup: v1alpha1.GroupVersion.Group,
+			}),
+		},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list IPAddresses: %w", err)
+	}
+	return addresses.Items, nil
+}
+
+func (r *poolReconciler) reconcileStatus(
+	ctx context.Context,
+	pool genericNutanixIPPool,
+	kind string,
+) error {
+	addresses, err := r.poolAddresses(ctx, pool, kind)
+	if err != nil {
+		return err
+	}
+	reserved := int64(len(addresses))
+
+	ctx = pcclient.WithPool(ctx, poolMetricLabel(pool))
+	nutanixClient, err := getClientForPool(pool, r.pcClientGetter, r.secretInformer, r.cmInformer)
+	if err != nil {
+		conditions.Set(pool, metav1.Condition{
+			Type:    v1alpha1.NutanixIPPoolCredentialsValidCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1alpha1.NutanixIPPoolCredentialsInvalidReason,
+			Message: err.Error(),
+		})
+		markPoolConditionsUnknown(
+			pool,
+			v1alpha1.NutanixIPPoolCredentialsInvalidReason,
+			v1alpha1.NutanixIPPoolPrismCentralReachableCondition,
+			v1alpha1.NutanixIPPoolClusterResolvedCondition,
+			v1alpha1.NutanixIPPoolSubnetResolvedCondition,
+			v1alpha1.NutanixIPPoolFallbackSubnetsResolvedCondition,
+		)
+		return fmt.Errorf("failed to get Nutanix client: %w", err)
+	}
+
+	// Drop the subnets and clusters cached for the Prism Central if the pool spec changed since its subnets were
+	// last resolved, so that neither the pool nor its claims use stale resolutions.
+	if resolved := conditions.Get(pool, v1alpha1.NutanixIPPoolSubnetResolvedCondition); resolved != nil &&
+		resolved.ObservedGeneration != pool.GetGeneration() {
+		pcclient.InvalidateResolutionCache(prismCentralEndpoint(pool.PoolSpec().PrismCentral))
+	}
+
+	cluster := ptr.Deref(pool.PoolSpec().Cluster, "")
+	if cluster != "" {
+		if _, err := nutanixClient.Cluster().GetCluster(ctx, cluster); err != nil {
+			markPrismCentralError(
+				pool,
+				err,
+				v1alpha1.NutanixIPPoolClusterResolvedCondition,
+				v1alpha1.NutanixIPPoolClusterResolutionFailedReason,
+				v1alpha1.NutanixIPPoolSubnetResolvedCondition,
+				v1alpha1.NutanixIPPoolFallbackSubnetsResolvedCondition,
+			)
+			return fmt.Errorf("failed to get cluster: %w", err)
+		}
+		markPrismCentralReachable(pool)
+		conditions.Set(pool, metav1.Condition{
+			Type:   v1alpha1.NutanixIPPoolClusterResolvedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: v1alpha1.NutanixIPPoolClusterResolvedReason,
+		})
+	} else {
+		conditions.Set(pool, metav1.Condition{
+			Type:   v1alpha1.NutanixIPPoolClusterResolvedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: v1alpha1.NutanixIPPoolClusterNotRequiredReason,
+		})
+	}
+
+	// Only get the subnets from Prism Central on the periodic requeue or if the pool spec changed, as the pool is
+	// also reconciled whenever an IPAddress allocated from it changes.
+	now := time.Now()
+	refresh := r.subnetRefreshes.due(pool, r.opts.poolSyncPeriod, now)
+
+	subnet, err := nutanixClient.Networking().GetSubnet(
+		ctx,
+		pool.PoolSpec().Subnet,
+		pcclient.GetSubnetOpts{Cluster: cluster, Refresh: refresh},
+	)
+	if err != nil {
+		markPrismCentralError(
+			pool,
+			err,
+			v1alpha1.NutanixIPPoolSubnetResolvedCondition,
+			v1alpha1.NutanixIPPoolSubnetResolutionFailedReason,
+			v1alpha1.NutanixIPPoolFallbackSubnetsResolvedCondition,
+		)
+		return fmt.Errorf("failed to get subnet: %w", err)
+	}
+	subnets := []*pcclient.Subnet{subnet}
+
+	var ipv6Subnet *pcclient.Subnet
+	if pool.PoolSpec().IPv6Subnet != nil {
+		ipv6Subnet, err = nutanixClient.Networking().GetSubnet(
+			ctx,
+			*pool.PoolSpec().IPv6Subnet,
+			pcclient.GetSubnetOpts{Cluster: cluster, Refresh: refresh},
+		)
+		if err != nil {
+			markPrismCentralError(
+				pool,
+				err,
+				v1alpha1.NutanixIPPoolSubnetResolvedCondition,
+				v1alpha1.NutanixIPPoolSubnetResolutionFailedReason,
+				v1alpha1.NutanixIPPoolFallbackSubnetsResolvedCondition,
+			)
+			return fmt.Errorf("failed to get IPv6 subnet: %w", err)
+		}
+		subnets = append(subnets, ipv6Subnet)
+	}
+
+	markPrismCentralReachable(pool)
+	// The resolution cache is invalidated by the observed generation of this condition, see above.
+	conditions.Set(pool, metav1.Condition{
+		Type:               v1alpha1.NutanixIPPoolSubnetResolvedCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             v1alpha1.NutanixIPPoolSubnetResolvedReason,
+		ObservedGeneration: pool.GetGeneration(),
+	})
+
+	// Fallback subnets that cannot be resolved do not block the pool, as claims are still allocated IPs from the
+	// subnet of the pool and the other fallback subnets. They are reported by the FallbackSubnetsResolved condition.
+	fallbackSubnets := make([]*pcclient.Subnet, 0, len(pool.PoolSpec().FallbackSubnets))
+	var fallbackErrs []error
+	for _, fallback := range allocationSubnets(pool.PoolSpec())[1:] {
+		fallbackSubnet, err := nutanixClient.Networking().GetSubnet(
+			ctx,
+			fallback.name,
+			pcclient.GetSubnetOpts{Cluster: fallback.cluster, Refresh: refresh},
+		)
+		if err != nil {
+			fallbackErrs = append(fallbackErrs, fmt.Errorf("failed to get fallback subnet %s: %w", fallback.name, err))
+			continue
+		}
+		fallbackSubnets = append(fallbackSubnets, fallbackSubnet)
+	}
+	subnets = append(subnets, fallbackSubnets...)
+	switch {
+	case len(fallbackErrs) > 0:
+		conditions.Set(pool, metav1.Condition{
+			Type:    v1alpha1.NutanixIPPoolFallbackSubnetsResolvedCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1alpha1.NutanixIPPoolFallbackSubnetResolutionFailedReason,
+			Message: kerrors.NewAggregate(fallbackErrs).Error(),
+		})
+	case len(fallbackSubnets) > 0:
+		conditions.Set(pool, metav1.Condition{
+			Type:   v1alpha1.NutanixIPPoolFallbackSubnetsResolvedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: v1alpha1.NutanixIPPoolFallbackSubnetsResolvedReason,
+		})
+	default:
+		conditions.Set(pool, metav1.Condition{
+			Type:   v1alpha1.NutanixIPPoolFallbackSubnetsResolvedCondition,
+			Status: metav1.ConditionTrue,
+			Reason: v1alpha1.NutanixIPPoolFallbackSubnetsNotRequiredReason,
+		})
+	}
+	// Refresh the subnets again on the next reconcile if a fallback subnet could not be resolved.
+	if refresh && len(fallbackErrs) == 0 {
+		r.subnetRefreshes.done(pool, now)
+	}
+
+	var total, used, free int64
+	usageReported := true
+	for _, s := range subnets {
+		count, err := poolutil.IPSetCount(s.Pools())
+		if err != nil {
+			// IPv6 pools can contain more IPs than fit in an int64.
+			count = math.MaxInt64
+		}
+		total = saturatingAdd(total, count)
+
+		usage := s.IPUsage()
+		if usage == nil {
+			usageReported = false
+			continue
+		}
+		used = saturatingAdd(used, usage.Assigned)
+		free = saturatingAdd(free, usage.Free)
+	}
+
+	// Fall back to the addresses reserved by this provider if Prism Central does not report the subnet usage.
+	if !usageReported {
+		used, free = reserved, max(total-reserved, 0)
+	}
+
+	status := pool.PoolStatus()
+	status.Addresses = &v1alpha1.NutanixIPPoolStatusIPAddresses{
+		Total:    total,
+		Used:     used,
+		Free:     free,
+		Reserved: reserved,
+	}
+	gateway := subnet.GatewayFor(netip.IPv4Unspecified())
+	if !gateway.IsValid() {
+		gateway = subnet.GatewayFor(netip.IPv6Unspecified())
+	}
+	status.Subnet = newStatusSubnet(subnet, subnet.Prefix(), gateway)
+	status.IPv6Subnet = nil
+	if ipv6Subnet != nil {
+		status.IPv6Subnet = newStatusSubnet(
+			ipv6Subnet,
+			ipv6Subnet.PrefixFor(netip.IPv6Unspecified()),
+			ipv6Subnet.GatewayFor(netip.IPv6Unspecified()),
+		)
+	}
+	status.FallbackSubnets = nil
+	for _, fallbackSubnet := range fallbackSubnets {
+		status.FallbackSubnets = append(status.FallbackSubnets, *newStatusSubnet(
+			fallbackSubnet,
+			fallbackSubnet.Prefix(),
+			fallbackSubnet.GatewayFor(netip.IPv4Unspecified()),
+		))
+	}
+	status.ClusterExtID = ""
+	if subnet.ClusterExtID() != uuid.Nil {
+		status.ClusterExtID = subnet.ClusterExtID().String()
+	}
+
+	recordPoolAddressMetrics(pool, kind)
+
+	// Retry the fallback subnets that could not be resolved with backoff, now that the status is up to date.
+	return kerrors.NewAggregate(fallbackErrs)
+}
+
+func newStatusSubnet(
+	subnet *pcclient.Subnet,
+	prefix int32,
+	gateway netip.Addr,
+) *v1alpha1.NutanixIPPoolStatusSubnet {
+	statusSubnet := &v1alpha1.NutanixIPPoolStatusSubnet{
+		ExtID:         subnet.ExtID().String(),
+		Prefix:        prefix,
+		SearchDomains: subnet.SearchDomains(),
+	}
+	if gateway.IsValid() {
+		statusSubnet.Gateway = gateway.String()
+	}
+	for _, server := range subnet.DNSServers() {
+		statusSubnet.DNSServers = append(statusSubnet.DNSServers, server.String())
+	}
+	return statusSubnet
+}
+
+func saturatingAdd(a, b int64) int64 {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+// setPoolReadyCondition summarizes the conditions set from the calls to Prism Central into the Ready condition.
+func setPoolReadyCondition(pool genericNutanixIPPool) error {
+	return conditions.SetSummaryCondition(pool, pool, v1alpha1.NutanixIPPoolReadyCondition,
+		conditions.ForConditionTypes{
+			v1alpha1.NutanixIPPoolPrismCentralReachableCondition,
+			v1alpha1.NutanixIPPoolCredentialsValidCondition,
+			v1alpha1.NutanixIPPoolClusterResolvedCondition,
+			v1alpha1.NutanixIPPoolSubnetResolvedCondition,
+		},
+	)
+}
+
+func markPrismCentralReachable(pool genericNutanixIPPool) {
+	conditions.Set(pool, metav1.Condition{
+		Type:   v1alpha1.NutanixIPPoolPrismCentralReachableCondition,
+		Status: metav1.ConditionTrue,
+		Reason: v1alpha1.NutanixIPPoolPrismCentralReachableReason,
+	})
+	conditions.Set(pool, metav1.Condition{
+		Type:   v1alpha1.NutanixIPPoolCredentialsValidCondition,
+		Status: metav1.ConditionTrue,
+		Reason: v1alpha1.NutanixIPPoolCredentialsValidReason,
+	})
+}
+
+// markPrismCentralError sets the pool conditions from an error returned by Prism Central. Depending on the
+// error, either PrismCentralReachable, CredentialsValid or the given condition is set to false, and the
+// conditions that could not be checked as a result are set to unknown. Throttled requests were never sent to Prism
+// Central, so they leave the conditions unchanged.
+func markPrismCentralError(
+	pool genericNutanixIPPool,
+	err error,
+	conditionType, reason string,
+	dependentConditionTypes ...string,
+) {
+	switch {
+	case errors.Is(err, pcclient.ErrThrottled):
+		return
+	case errors.Is(err, pcclient.ErrCircuitOpen):
+		conditions.Set(pool, metav1.Condition{
+			Type:    v1alpha1.NutanixIPPoolPrismCentralReachableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1alpha1.NutanixIPPoolPrismCentralCircuitOpenReason,
+			Message: err.Error(),
+		})
+		markPoolConditionsUnknown(
+			pool,
+			v1alpha1.NutanixIPPoolPrismCentralCircuitOpenReason,
+			append(
+				[]string{v1alpha1.NutanixIPPoolCredentialsValidCondition, conditionType},
+				dependentConditionTypes...,
+			)...,
+		)
+	case isPrismCentralUnreachable(err):
+		conditions.Set(pool, metav1.Condition{
+			Type:    v1alpha1.NutanixIPPoolPrismCentralReachableCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1alpha1.NutanixIPPoolPrismCentralUnreachableReason,
+			Message: err.Error(),
+		})
+		markPoolConditionsUnknown(
+			pool,
+			v1alpha1.NutanixIPPoolPrismCentralUnreachableReason,
+			append(
+				[]string{v1alpha1.NutanixIPPoolCredentialsValidCondition, conditionType},
+				dependentConditionTypes...,
+			)...,
+		)
+	case isPrismCentralUnauthorized(err):
+		conditions.Set(pool, metav1.Condition{
+			Type:   v1alpha1.NutanixIPPoolPrismCentralReachableCondition,
+			Status: metav1.ConditionTrue,
+			Reason: v1alpha1.NutanixIPPoolPrismCentralReachableReason,
+		})
+		conditions.Set(pool, metav1.Condition{
+			Type:    v1alpha1.NutanixIPPoolCredentialsValidCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1alpha1.NutanixIPPoolCredentialsInvalidReason,
+			Message: err.Error(),
+		})
+		markPoolConditionsUnknown(
+			pool,
+			v1alpha1.NutanixIPPoolCredentialsInvalidReason,
+			append([]string{conditionType}, dependentConditionTypes...)...,
+		)
+	default:
+		markPrismCentralReachable(pool)
+		conditions.Set(pool, metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: err.Error(),
+		})
+		markPoolConditionsUnknown(pool, reason, dependentConditionTypes...)
+	}
+}
+
+func markPoolConditionsUnknown(pool genericNutanixIPPool, reason string, conditionTypes ...string) {
+	for _, conditionType := range conditionTypes {
+		conditions.Set(pool, metav1.Condition{
+			Type:   conditionType,
+			Status: metav1.ConditionUnknown,
+			Reason: reason,
+		})
+	}
+}
+
+func isPrismCentralUnreachable(err error) bool {
+	var (
+		urlErr *url.Error
+		netErr net.Error
+	)
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+func isPrismCentralUnauthorized(err error) bool {
+	return errors.Is(err, pcclient.ErrUnauthorized)
+}
@@ -0,0 +1,623 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"net/url"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/mock/gomock"
+	"go4.org/netipx"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ipamv1 "sigs.k8s.io/cluster-api/api/ipam/v1beta2"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/nutanix-cloud-native/prism-go-client/environment/credentials"
+
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
+	pcclient "github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/client"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/controllers/mockclient"
+)
+
+func newIPAddress(name, namespace, poolKind, poolName, address string) ipamv1.IPAddress {
+	return ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: ipamv1.IPAddressClaimReference{
+				Name: name,
+			},
+			PoolRef: ipamv1.IPPoolReference{
+				APIGroup: v1alpha1.GroupVersion.Group,
+				Kind:     poolKind,
+				Name:     poolName,
+			},
+			Address: address,
+			Prefix:  ptr.To[int32](24),
+		},
+	}
+}
+
+var _ = Describe("NutanixIPPoolReconciler", func() {
+	const poolName = "test-pool"
+
+	var (
+		namespace    string
+		pool         v1alpha1.NutanixIPPool
+		poolPCClient *mockclient.MockClient
+		mockNC       *mockclient.MockNetworkingClient
+		reconciler   *NutanixIPPoolReconciler
+	)
+
+	BeforeEach(func() {
+		ns, err := env.CreateNamespace(context.Background(), "test-ns")
+		Expect(err).NotTo(HaveOccurred())
+		namespace = ns.Name
+
+		mockController = gomock.NewController(GinkgoT())
+		DeferCleanup(func() {
+			Expect(mockController.Satisfied()).To(BeTrue())
+		})
+		DeferCleanup(mockController.Finish)
+
+		poolPCClient = mockclient.NewMockClient(mockController)
+		mockNC = mockclient.NewMockNetworkingClient(mockController)
+		poolPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+
+		reconciler = &NutanixIPPoolReconciler{
+			poolReconciler: poolReconciler{
+				client:         env,
+				secretInformer: secretInformer,
+				pcClientGetter: func(_ pcclient.CachedClientParams) (pcclient.Client, error) {
+					return poolPCClient, nil
+				},
+				opts:            DefaultReconcilerOptions(),
+				subnetRefreshes: newSubnetRefreshes(),
+			},
+		}
+
+		secret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-secret",
+				Namespace: namespace,
+			},
+			StringData: map[string]string{
+				credentials.KeyName: `
+		[
+		  {
+		    "type": "basic_auth",
+		    "data": {
+		      "prismCentral":{
+		        "username": "auser",
+		        "password": "apassword"
+		      }
+		    }
+		  }
+		]`,
+			},
+		}
+		Expect(env.CreateAndWait(context.Background(), &secret)).To(Succeed())
+
+		pool = v1alpha1.NutanixIPPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      poolName,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.NutanixIPPoolSpec{
+				PrismCentral: v1alpha1.PrismCentral{
+					Address: "prism.example.com",
+					Port:    9440,
+					CredentialsSecretRef: v1alpha1.LocalSecretRef{
+						Name: "test-secret",
+					},
+				},
+				Subnet: uuid.NewString(),
+			},
+		}
+		Expect(env.CreateAndWait(context.Background(), &pool)).To(Succeed())
+		DeferCleanup(env.CleanupAndWait, context.Background(), &pool, &secret)
+		// The pool finalizer added by the reconciler must be removed for the pool to be deleted on cleanup, as the
+		// reconciler is not running in the test environment.
+		DeferCleanup(func() {
+			Eventually(func(g Gomega) {
+				current := &v1alpha1.NutanixIPPool{}
+				err := env.Get(context.Background(), client.ObjectKeyFromObject(&pool), current)
+				if apierrors.IsNotFound(err) {
+					return
+				}
+				g.Expect(err).NotTo(HaveOccurred())
+				if controllerutil.RemoveFinalizer(current, v1alpha1.ProtectPoolFinalizer) {
+					g.Expect(env.Update(context.Background(), current)).To(Succeed())
+				}
+			}).Should(Succeed())
+		})
+	})
+
+	reconcilePool := func() (ctrl.Result, error) {
+		return reconciler.Reconcile(context.Background(), ctrl.Request{
+			NamespacedName: client.ObjectKeyFromObject(&pool),
+		})
+	}
+
+	It("should populate the status with the subnet capacity and usage", func() {
+		address := newIPAddress("test", namespace, v1alpha1.NutanixIPPoolKind, poolName, "10.0.0.10")
+		Expect(env.CreateAndWait(context.Background(), &address)).To(Succeed())
+		DeferCleanup(env.CleanupAndWait, context.Background(), &address)
+
+		subnetExtID := uuid.New()
+		clusterExtID := uuid.New()
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(
+				subnetExtID,
+				24,
+				pcclient.WithSubnetPools(mustIPSet("10.0.0.10-10.0.0.19")),
+				pcclient.WithSubnetIPUsage(pcclient.SubnetIPUsage{Assigned: 3, Free: 7}),
+				pcclient.WithSubnetClusterExtID(clusterExtID),
+				pcclient.WithSubnetGateway(netip.MustParseAddr("10.0.0.1")),
+				pcclient.WithSubnetDNSServers(netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("10.0.0.3")),
+				pcclient.WithSubnetSearchDomains("example.com"),
+			), nil,
+		)
+
+		result, err := reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reconciler.opts.poolSyncPeriod))
+
+		Eventually(func(g Gomega) *v1alpha1.NutanixIPPoolStatusIPAddresses {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Status.Addresses
+		}).Should(Equal(&v1alpha1.NutanixIPPoolStatusIPAddresses{
+			Total:    10,
+			Used:     3,
+			Free:     7,
+			Reserved: 1,
+		}))
+		Expect(pool.Status.Subnet).To(Equal(&v1alpha1.NutanixIPPoolStatusSubnet{
+			ExtID:         subnetExtID.String(),
+			Prefix:        24,
+			Gateway:       "10.0.0.1",
+			DNSServers:    []string{"10.0.0.2", "10.0.0.3"},
+			SearchDomains: []string{"example.com"},
+		}))
+		Expect(pool.Status.ClusterExtID).To(Equal(clusterExtID.String()))
+		Expect(conditions.IsTrue(&pool, v1alpha1.NutanixIPPoolReadyCondition)).To(BeTrue())
+		Expect(conditions.GetReason(&pool, v1alpha1.NutanixIPPoolClusterResolvedCondition)).
+			To(Equal(v1alpha1.NutanixIPPoolClusterNotRequiredReason))
+		Expect(pool.Finalizers).To(ContainElement(v1alpha1.ProtectPoolFinalizer))
+
+		poolLabel := client.ObjectKeyFromObject(&pool).String()
+		Expect(testutil.ToFloat64(poolAllocatedAddresses.WithLabelValues(v1alpha1.NutanixIPPoolKind, poolLabel))).
+			To(BeNumerically("==", 1))
+		Expect(testutil.ToFloat64(poolFreeAddresses.WithLabelValues(v1alpha1.NutanixIPPoolKind, poolLabel))).
+			To(BeNumerically("==", 7))
+	})
+
+	It("should fall back to the reserved count when the subnet usage is not reported", func() {
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(
+				uuid.New(),
+				24,
+				pcclient.WithSubnetPools(mustIPSet("10.0.0.10-10.0.0.19")),
+			), nil,
+		)
+
+		_, err := reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) *v1alpha1.NutanixIPPoolStatusIPAddresses {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Status.Addresses
+		}).Should(Equal(&v1alpha1.NutanixIPPoolStatusIPAddresses{
+			Total: 10,
+			Used:  0,
+			Free:  10,
+		}))
+		Expect(pool.Status.ClusterExtID).To(BeEmpty())
+	})
+
+	It("should only refresh the subnet on the periodic requeue or when the pool spec changes", func() {
+		subnet := pcclient.NewSubnet(uuid.New(), 24, pcclient.WithSubnetPools(mustIPSet("10.0.0.10-10.0.0.19")))
+		gomock.InOrder(
+			mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, pcclient.GetSubnetOpts{Refresh: true}).
+				Return(subnet, nil),
+			mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, pcclient.GetSubnetOpts{}).
+				Return(subnet, nil),
+			mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, pcclient.GetSubnetOpts{Refresh: true}).
+				Return(subnet, nil),
+		)
+
+		_, err := reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+		pool.Spec.ExcludedAddresses = []string{"10.0.0.10"}
+		Expect(env.Update(context.Background(), &pool)).To(Succeed())
+		Eventually(func(g Gomega) int64 {
+			current := &v1alpha1.NutanixIPPool{}
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), current)).To(Succeed())
+			return current.Generation
+		}).Should(Equal(pool.Generation))
+
+		_, err = reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should refresh the subnet when the subnet of the pool changes", func() {
+		subnet := pcclient.NewSubnet(uuid.New(), 24, pcclient.WithSubnetPools(mustIPSet("10.0.0.10-10.0.0.19")))
+		newSubnet := pcclient.NewSubnet(uuid.New(), 24, pcclient.WithSubnetPools(mustIPSet("10.0.1.10-10.0.1.19")))
+		newSubnetName := uuid.NewString()
+		gomock.InOrder(
+			mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, pcclient.GetSubnetOpts{Refresh: true}).
+				Return(subnet, nil),
+			mockNC.EXPECT().GetSubnet(gomock.Any(), newSubnetName, pcclient.GetSubnetOpts{Refresh: true}).
+				Return(newSubnet, nil),
+		)
+
+		_, err := reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			resolved := conditions.Get(&pool, v1alpha1.NutanixIPPoolSubnetResolvedCondition)
+			g.Expect(resolved).NotTo(BeNil())
+			g.Expect(resolved.ObservedGeneration).To(Equal(pool.Generation))
+		}).Should(Succeed())
+		pool.Spec.Subnet = newSubnetName
+		Expect(env.Update(context.Background(), &pool)).To(Succeed())
+		Eventually(func(g Gomega) string {
+			current := &v1alpha1.NutanixIPPool{}
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), current)).To(Succeed())
+			return current.Spec.Subnet
+		}).Should(Equal(newSubnetName))
+
+		_, err = reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			g.Expect(pool.Status.Subnet).NotTo(BeNil())
+			g.Expect(pool.Status.Subnet.ExtID).To(Equal(newSubnet.ExtID().String()))
+			g.Expect(conditions.Get(&pool, v1alpha1.NutanixIPPoolSubnetResolvedCondition).ObservedGeneration).
+				To(Equal(pool.Generation))
+		}).Should(Succeed())
+	})
+
+	It("should sum the capacity and usage of both subnets of a dual-stack pool", func() {
+		pool.Spec.IPv6Subnet = ptr.To(uuid.NewString())
+		Expect(env.Update(context.Background(), &pool)).To(Succeed())
+		Eventually(func(g Gomega) *string {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Spec.IPv6Subnet
+		}).ShouldNot(BeNil())
+
+		ipv6SubnetExtID := uuid.New()
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(
+				uuid.New(),
+				24,
+				pcclient.WithSubnetPools(mustIPSet("10.0.0.10-10.0.0.19")),
+				pcclient.WithSubnetIPUsage(pcclient.SubnetIPUsage{Assigned: 3, Free: 7}),
+			), nil,
+		)
+		mockNC.EXPECT().GetSubnet(gomock.Any(), *pool.Spec.IPv6Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(
+				ipv6SubnetExtID,
+				64,
+				pcclient.WithSubnetIPv6Prefix(64),
+				pcclient.WithSubnetPools(mustIPSet("fd00::10-fd00::1f")),
+				pcclient.WithSubnetIPUsage(pcclient.SubnetIPUsage{Assigned: 1, Free: 15}),
+			), nil,
+		)
+
+		_, err := reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) *v1alpha1.NutanixIPPoolStatusIPAddresses {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Status.Addresses
+		}).Should(Equal(&v1alpha1.NutanixIPPoolStatusIPAddresses{
+			Total: 26,
+			Used:  4,
+			Free:  22,
+		}))
+		Expect(pool.Status.IPv6Subnet).To(Equal(&v1alpha1.NutanixIPPoolStatusSubnet{
+			ExtID:  ipv6SubnetExtID.String(),
+			Prefix: 64,
+		}))
+	})
+
+	It("should resolve the fallback subnets and sum their capacity and usage", func() {
+		pool.Spec.FallbackSubnets = []v1alpha1.NutanixIPPoolFallbackSubnet{{Subnet: uuid.NewString()}}
+		Expect(env.Update(context.Background(), &pool)).To(Succeed())
+		Eventually(func(g Gomega) []v1alpha1.NutanixIPPoolFallbackSubnet {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Spec.FallbackSubnets
+		}).Should(HaveLen(1))
+
+		fallbackSubnetExtID := uuid.MustParse(pool.Spec.FallbackSubnets[0].Subnet)
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(
+				uuid.New(),
+				24,
+				pcclient.WithSubnetPools(mustIPSet("10.0.0.10-10.0.0.19")),
+				pcclient.WithSubnetIPUsage(pcclient.SubnetIPUsage{Assigned: 10, Free: 0}),
+			), nil,
+		)
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.FallbackSubnets[0].Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(
+				fallbackSubnetExtID,
+				24,
+				pcclient.WithSubnetPools(mustIPSet("10.0.1.10-10.0.1.19")),
+				pcclient.WithSubnetIPUsage(pcclient.SubnetIPUsage{Assigned: 2, Free: 8}),
+			), nil,
+		)
+
+		_, err := reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) *v1alpha1.NutanixIPPoolStatusIPAddresses {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Status.Addresses
+		}).Should(Equal(&v1alpha1.NutanixIPPoolStatusIPAddresses{
+			Total: 20,
+			Used:  12,
+			Free:  8,
+		}))
+		Expect(pool.Status.FallbackSubnets).To(Equal([]v1alpha1.NutanixIPPoolStatusSubnet{{
+			ExtID:  fallbackSubnetExtID.String(),
+			Prefix: 24,
+		}}))
+	})
+
+	It("should keep the pool ready when a fallback subnet cannot be resolved", func() {
+		pool.Spec.FallbackSubnets = []v1alpha1.NutanixIPPoolFallbackSubnet{{Subnet: uuid.NewString()}}
+		Expect(env.Update(context.Background(), &pool)).To(Succeed())
+		Eventually(func(g Gomega) []v1alpha1.NutanixIPPoolFallbackSubnet {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Spec.FallbackSubnets
+		}).Should(HaveLen(1))
+
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(
+				uuid.New(),
+				24,
+				pcclient.WithSubnetPools(mustIPSet("10.0.0.10-10.0.0.19")),
+				pcclient.WithSubnetIPUsage(pcclient.SubnetIPUsage{Assigned: 2, Free: 8}),
+			), nil,
+		)
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.FallbackSubnets[0].Subnet, gomock.Any()).Return(
+			nil, &pcclient.Error{Kind: pcclient.ErrSubnetNotFound},
+		)
+
+		_, err := reconcilePool()
+		Expect(err).To(MatchError(pcclient.ErrSubnetNotFound))
+
+		Eventually(func(g Gomega) *v1alpha1.NutanixIPPoolStatusIPAddresses {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Status.Addresses
+		}).Should(Equal(&v1alpha1.NutanixIPPoolStatusIPAddresses{
+			Total: 10,
+			Used:  2,
+			Free:  8,
+		}))
+		Expect(pool.Status.FallbackSubnets).To(BeEmpty())
+		Expect(conditions.IsTrue(&pool, v1alpha1.NutanixIPPoolReadyCondition)).To(BeTrue())
+		Expect(conditions.IsTrue(&pool, v1alpha1.NutanixIPPoolSubnetResolvedCondition)).To(BeTrue())
+		Expect(conditions.Get(&pool, v1alpha1.NutanixIPPoolFallbackSubnetsResolvedCondition)).To(And(
+			HaveField("Status", metav1.ConditionFalse),
+			HaveField("Reason", v1alpha1.NutanixIPPoolFallbackSubnetResolutionFailedReason),
+			HaveField("Message", ContainSubstring(pool.Spec.FallbackSubnets[0].Subnet)),
+		))
+	})
+
+	It("should block the deletion of the pool while IPAddresses reference it", func() {
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(uuid.New(), 24), nil,
+		)
+		_, err := reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func(g Gomega) []string {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Finalizers
+		}).Should(ContainElement(v1alpha1.ProtectPoolFinalizer))
+
+		address := newIPAddress("test", namespace, v1alpha1.NutanixIPPoolKind, poolName, "10.0.0.10")
+		Expect(env.CreateAndWait(context.Background(), &address)).To(Succeed())
+
+		Expect(env.Delete(context.Background(), &pool)).To(Succeed())
+		Eventually(func(g Gomega) bool {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.DeletionTimestamp.IsZero()
+		}).Should(BeFalse())
+
+		_, err = reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func(g Gomega) *metav1.Condition {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return conditions.Get(&pool, v1alpha1.NutanixIPPoolDeletingCondition)
+		}).Should(And(
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", v1alpha1.NutanixIPPoolDeletingWaitingForIPAddressesReason),
+			HaveField("Message", ContainSubstring(client.ObjectKeyFromObject(&address).String())),
+		))
+
+		Expect(env.CleanupAndWait(context.Background(), &address)).To(Succeed())
+		_, err = reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() bool {
+			return apierrors.IsNotFound(
+				env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &v1alpha1.NutanixIPPool{}),
+			)
+		}).Should(BeTrue())
+	})
+
+	It("should mark the pool as not ready when the subnet cannot be resolved", func() {
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			nil, errors.New("subnet not found"),
+		)
+
+		_, err := reconcilePool()
+		Expect(err).To(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			g.Expect(conditions.IsFalse(&pool, v1alpha1.NutanixIPPoolReadyCondition)).To(BeTrue())
+		}).Should(Succeed())
+		Expect(conditions.IsTrue(&pool, v1alpha1.NutanixIPPoolPrismCentralReachableCondition)).To(BeTrue())
+		Expect(conditions.IsTrue(&pool, v1alpha1.NutanixIPPoolCredentialsValidCondition)).To(BeTrue())
+		Expect(conditions.Get(&pool, v1alpha1.NutanixIPPoolSubnetResolvedCondition)).To(
+			HaveField("Reason", v1alpha1.NutanixIPPoolSubnetResolutionFailedReason),
+		)
+	})
+
+	It("should mark the pool as not ready when Prism Central is unreachable", func() {
+		pool.Spec.Cluster = ptr.To("test-cluster")
+		Expect(env.Update(context.Background(), &pool)).To(Succeed())
+		Eventually(func(g Gomega) *string {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Spec.Cluster
+		}).Should(HaveValue(Equal("test-cluster")))
+
+		mockCC := mockclient.NewMockClusterClient(mockController)
+		poolPCClient.EXPECT().Cluster().Return(mockCC).AnyTimes()
+		mockCC.EXPECT().GetCluster(gomock.Any(), "test-cluster").Return(
+			nil, &url.Error{Op: "Get", URL: "https://prism.example.com:9440", Err: errors.New("connection refused")},
+		)
+
+		_, err := reconcilePool()
+		Expect(err).To(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			g.Expect(conditions.IsFalse(&pool, v1alpha1.NutanixIPPoolPrismCentralReachableCondition)).To(BeTrue())
+		}).Should(Succeed())
+		Expect(conditions.IsFalse(&pool, v1alpha1.NutanixIPPoolReadyCondition)).To(BeTrue())
+		for _, conditionType := range []string{
+			v1alpha1.NutanixIPPoolCredentialsValidCondition,
+			v1alpha1.NutanixIPPoolClusterResolvedCondition,
+			v1alpha1.NutanixIPPoolSubnetResolvedCondition,
+		} {
+			Expect(conditions.IsUnknown(&pool, conditionType)).To(BeTrue(), conditionType)
+		}
+	})
+
+	It("should mark the credentials invalid when Prism Central rejects them", func() {
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			nil, &pcclient.Error{Kind: pcclient.ErrUnauthorized, StatusCode: 401},
+		)
+
+		_, err := reconcilePool()
+		Expect(err).To(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			g.Expect(conditions.IsFalse(&pool, v1alpha1.NutanixIPPoolCredentialsValidCondition)).To(BeTrue())
+		}).Should(Succeed())
+		Expect(conditions.IsTrue(&pool, v1alpha1.NutanixIPPoolPrismCentralReachableCondition)).To(BeTrue())
+		Expect(conditions.IsUnknown(&pool, v1alpha1.NutanixIPPoolSubnetResolvedCondition)).To(BeTrue())
+	})
+
+	It("should mark Prism Central unreachable while its circuit breaker is open", func() {
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			nil, &pcclient.Error{Kind: pcclient.ErrCircuitOpen},
+		)
+
+		_, err := reconcilePool()
+		Expect(err).To(MatchError(pcclient.ErrCircuitOpen))
+
+		Eventually(func(g Gomega) {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			g.Expect(conditions.GetReason(&pool, v1alpha1.NutanixIPPoolPrismCentralReachableCondition)).
+				To(Equal(v1alpha1.NutanixIPPoolPrismCentralCircuitOpenReason))
+		}).Should(Succeed())
+		Expect(conditions.IsFalse(&pool, v1alpha1.NutanixIPPoolPrismCentralReachableCondition)).To(BeTrue())
+		Expect(conditions.IsUnknown(&pool, v1alpha1.NutanixIPPoolSubnetResolvedCondition)).To(BeTrue())
+	})
+
+	It("should label the credentials secret for clusterctl move", func() {
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil,
+		).AnyTimes()
+
+		// The secret is labeled once the secret informer has observed it.
+		Eventually(func(g Gomega) map[string]string {
+			_, err := reconcilePool()
+			g.Expect(err).NotTo(HaveOccurred())
+			secret := &corev1.Secret{}
+			g.Expect(
+				env.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "test-secret"}, secret),
+			).To(Succeed())
+			return secret.Labels
+		}).Should(HaveKeyWithValue(clusterctlv1.ClusterctlMoveLabel, ""))
+	})
+
+	It("should requeue the pool and use a new client version when the credentials secret is rotated", func() {
+		var versions []string
+		reconciler.pcClientGetter = func(params pcclient.CachedClientParams) (pcclient.Client, error) {
+			Expect(params.Key()).To(Equal(client.ObjectKeyFromObject(&pool).String()))
+			versions = append(versions, params.(pcclient.VersionedClientParams).Version())
+			return poolPCClient, nil
+		}
+		mockNC.EXPECT().GetSubnet(gomock.Any(), pool.Spec.Subnet, gomock.Any()).Return(
+			pcclient.NewSubnet(uuid.MustParse(pool.Spec.Subnet), 24), nil,
+		).Times(2)
+
+		_, err := reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := &corev1.Secret{}
+		Expect(env.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: "test-secret"}, secret)).
+			To(Succeed())
+		secret.StringData = map[string]string{
+			credentials.KeyName: `[{"type": "basic_auth", "data": {"prismCentral": {
+				"username": "auser", "password": "rotated"
+			}}}]`,
+		}
+		Expect(env.Update(context.Background(), secret)).To(Succeed())
+		Eventually(func(g Gomega) {
+			cached, err := secretInformer.Lister().Secrets(namespace).Get(secret.Name)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cached.ResourceVersion).To(Equal(secret.ResourceVersion))
+		}).Should(Succeed())
+
+		Expect(credentialsToPools(env, v1alpha1.NutanixIPPoolKind)(context.Background(), secret)).To(ConsistOf(
+			ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&pool)},
+		))
+		Expect(credentialsToPools(env, v1alpha1.GlobalNutanixIPPoolKind)(context.Background(), secret)).To(BeEmpty())
+
+		_, err = reconcilePool()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions).To(HaveLen(2))
+		Expect(versions[1]).NotTo(Equal(versions[0]))
+	})
+})
+
+func mustIPSet(ranges ...string) *netipx.IPSet {
+	builder := &netipx.IPSetBuilder{}
+	for _, r := range ranges {
+		builder.AddRange(netipx.MustParseIPRange(r))
+	}
+	ipSet, err := builder.IPSet()
+	if err != nil {
+		panic(err)
+	}
+	return ipSet
+}
@@ -0,0 +1,445 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ipamv1 "sigs.k8s.io/cluster-api/api/ipam/v1beta2"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
+	pcclient "github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/client"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/clientcontext"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/index"
+)
+
+const (
+	// ReservationReleasedReason is the reason of the event emitted when an orphaned reservation is released.
+	ReservationReleasedReason = "ReservationReleased"
+
+	// OrphanedReservationReason is the reason of the event emitted in dry-run mode when an orphaned reservation
+	// would have been released.
+	OrphanedReservationReason = "OrphanedReservation"
+
+	// ReservationReleaseFailedReason is the reason of the event emitted when an orphaned reservation could not be
+	// released.
+	ReservationReleaseFailedReason = "ReservationReleaseFailed"
+)
+
+// ReservationGCReconciler periodically releases IPs reserved in the subnets of a pool under the client context of
+// an IPAddressClaim that no longer exists, e.g. because the claim finalizer was removed before the IP was
+// released.
+//
+// Reservations are matched against the client contexts of the claims of all pools sharing a subnet, so the
+// controller must be able to see all claims: it should not be enabled when the controller only watches a single
+// namespace. Only reservations whose client context starts with the configured client context prefix are released,
+// as a subnet may be shared with other management clusters.
+type ReservationGCReconciler struct {
+	client           ctrlclient.Client
+	watchFilterValue string
+	pcClientGetter   func(pcclient.CachedClientParams) (pcclient.Client, error)
+	secretInformer   coreinformers.SecretInformer
+	cmInformer       coreinformers.ConfigMapInformer
+	recorder         events.EventRecorder
+	opts             reconcilerOptions
+	now              func() time.Time
+
+	mu sync.Mutex
+	// orphanedSince tracks when each orphaned reservation was first seen, keyed by subnet extID and then by
+	// client context and address.
+	orphanedSince map[string]map[string]time.Time
+}
+
+func NewReservationGCReconciler(
+	client ctrlclient.Client,
+	watchFilter string,
+	secretInformer coreinformers.SecretInformer,
+	cmInformer coreinformers.ConfigMapInformer,
+	recorder events.EventRecorder,
+	opts reconcilerOptions,
+) *ReservationGCReconciler {
+	return &ReservationGCReconciler{
+		client:           client,
+		watchFilterValue: watchFilter,
+		pcClientGetter:   pcclient.GetClient,
+		secretInformer:   secretInformer,
+		cmInformer:       cmInformer,
+		recorder:         recorder,
+		opts:             opts,
+		now:              time.Now,
+		orphanedSince:    map[string]map[string]time.Time{},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager. This is a no-op unless reservation garbage
+// collection is enabled.
+func (r *ReservationGCReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if !r.opts.reservationGCEnabled {
+		return nil
+	}
+
+	for _, pool := range []struct {
+		obj  genericNutanixIPPool
+		kind string
+		name string
+	}{
+		{&v1alpha1.NutanixIPPool{}, v1alpha1.NutanixIPPoolKind, "nutanixippool-reservation-gc"},
+		{&v1alpha1.GlobalNutanixIPPool{}, v1alpha1.GlobalNutanixIPPoolKind, "globalnutanixippool-reservation-gc"},
+	} {
+		kind := pool.kind
+		err := ctrl.NewControllerManagedBy(mgr).
+			Named(pool.name).
+			For(pool.obj).
+			WithOptions(controller.Options{
+				MaxConcurrentReconciles: 1,
+			}).
+			WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(
+				mgr.GetScheme(), ctrl.LoggerFrom(ctx), r.watchFilterValue,
+			)).
+			Complete(reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+				return r.reconcile(ctx, req, kind)
+			}))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ReservationGCReconciler) reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+	kind string,
+) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	pool, err := r.getPool(ctx, kind, req.NamespacedName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if annotations.HasPaused(pool) || !pool.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	result := ctrl.Result{RequeueAfter: r.opts.reservationGCInterval}
+
+	subnets := poolSubnetExtIDs(pool)
+	if len(subnets) == 0 {
+		log.V(4).Info("Pool subnets are not resolved yet, skipping reservation garbage collection")
+		return result, nil
+	}
+
+	tmpl, err := clientcontext.Parse(ptr.Deref(pool.PoolSpec().ClientContextTemplate, ""))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("invalid clientContextTemplate of pool: %w", err)
+	}
+
+	clientContexts, ok, err := r.claimClientContextsForSubnets(ctx, pool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !ok {
+		log.V(4).Info(
+			"Not all pools have resolved subnets, skipping reservation garbage collection",
+		)
+		return result, nil
+	}
+
+	ctx = pcclient.WithPool(ctx, poolMetricLabel(pool))
+	nutanixClient, err := getClientForPool(pool, r.pcClientGetter, r.secretInformer, r.cmInformer)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get Nutanix client: %w", err)
+	}
+
+	var errs []error
+	for _, subnet := range subnets {
+		if err := r.collectSubnet(ctx, pool, nutanixClient, subnet, tmpl, clientContexts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return result, kerrors.NewAggregate(errs)
+}
+
+// collectSubnet releases the reservations in the subnet that were made for claims that no longer exist and
+// have been orphaned for longer than the grace period.
+func (r *ReservationGCReconciler) collectSubnet(
+	ctx context.Context,
+	pool genericNutanixIPPool,
+	nutanixClient pcclient.Client,
+	subnet string,
+	tmpl *clientcontext.Template,
+	clientContexts sets.Set[string],
+) error {
+	log := ctrl.LoggerFrom(ctx).WithValues("subnet", subnet)
+
+	reservedIPs, err := nutanixClient.Networking().ListReservedIPs(ctx, subnet, pcclient.ListReservedIPsOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to list reserved IPs in subnet %s: %w", subnet, err)
+	}
+
+	var orphaned []pcclient.ReservedIP
+	for _, reservedIP := range reservedIPs {
+		// Only reservations made with a client context rendered from the pool's template, or with a claim UID as
+		// client context, may have been made by this provider.
+		uid := reservedIP.ClientContext
+		if fields, ok := tmpl.ParseClientContext(reservedIP.ClientContext); ok {
+			uid = fields.UID
+		} else if _, err := uuid.Parse(reservedIP.ClientContext); err != nil {
+			continue
+		}
+		if clientContexts.Has(reservedIP.ClientContext) || clientContexts.Has(uid) {
+			continue
+		}
+		orphaned = append(orphaned, reservedIP)
+	}
+
+	now := r.now()
+	expired := r.trackOrphaned(subnet, orphaned, now)
+
+	var errs []error
+	for _, reservedIP := range expired {
+		// The subnet may be shared with other management clusters or other clients using the same client context
+		// scheme, so only reservations marked as owned by this management cluster are released.
+		if !r.ownsReservation(tmpl, reservedIP) {
+			log.Info(
+				"Found orphaned reservation not owned by this management cluster",
+				"address", reservedIP.Address,
+				"clientContext", reservedIP.ClientContext,
+			)
+			r.recorder.Eventf(
+				pool, nil, corev1.EventTypeNormal, OrphanedReservationReason, "Report",
+				"IP %s reserved in subnet %s for claim %s is not released as its client context does not start "+
+					"with the reservation GC client context prefix",
+				reservedIP.Address, subnet, reservedIP.ClientContext,
+			)
+			continue
+		}
+		if r.opts.reservationGCDryRun {
+			log.Info(
+				"Found orphaned reservation (dry run)",
+				"address", reservedIP.Address,
+				"clientContext", reservedIP.ClientContext,
+			)
+			r.recorder.Eventf(
+				pool, nil, corev1.EventTypeNormal, OrphanedReservationReason, "DryRun",
+				"IP %s reserved in subnet %s for deleted claim %s would be released",
+				reservedIP.Address, subnet, reservedIP.ClientContext,
+			)
+			continue
+		}
+
+		unreserveType, err := pcclient.UnreserveIPListFunc(reservedIP.Address.String())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := nutanixClient.Networking().UnreserveIPs(
+			ctx, unreserveType, subnet, pcclient.UnreserveIPOpts{},
+		); err != nil {
+			r.recorder.Eventf(
+				pool, nil, corev1.EventTypeWarning, ReservationReleaseFailedReason, "Unreserve",
+				"Failed to release IP %s reserved in subnet %s for deleted claim %s: %v",
+				reservedIP.Address, subnet, reservedIP.ClientContext, err,
+			)
+			errs = append(errs, fmt.Errorf("failed to release IP %s in subnet %s: %w", reservedIP.Address, subnet, err))
+			continue
+		}
+
+		log.Info(
+			"Released orphaned reservation",
+			"address", reservedIP.Address,
+			"clientContext", reservedIP.ClientContext,
+		)
+		r.recorder.Eventf(
+			pool, nil, corev1.EventTypeNormal, ReservationReleasedReason, "Unreserve",
+			"Released IP %s reserved in subnet %s for deleted claim %s",
+			reservedIP.Address, subnet, reservedIP.ClientContext,
+		)
+		r.forgetOrphaned(subnet, reservedIP)
+	}
+
+	return kerrors.NewAggregate(errs)
+}
+
+// ownsReservation returns whether the reservation is owned by this management cluster, i.e. whether its client
+// context was rendered from the pool's template and starts with the reservation GC client context prefix. Legacy
+// reservations made with a bare claim UID as client context are never owned, as they cannot be told apart from the
+// reservations of other clients.
+func (r *ReservationGCReconciler) ownsReservation(tmpl *clientcontext.Template, reservedIP pcclient.ReservedIP) bool {
+	prefix := r.opts.reservationGCClientContextPrefix
+	if prefix == "" || !strings.HasPrefix(reservedIP.ClientContext, prefix) {
+		return false
+	}
+	fields, ok := tmpl.ParseClientContext(reservedIP.ClientContext)
+	return ok && fields.UID != reservedIP.ClientContext
+}
+
+// trackOrphaned records the orphaned reservations of the subnet, forgetting any previously orphaned reservations
+// that no longer are, and returns the reservations that have been orphaned for longer than the grace period.
+func (r *ReservationGCReconciler) trackOrphaned(
+	subnet string,
+	orphaned []pcclient.ReservedIP,
+	now time.Time,
+) []pcclient.ReservedIP {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.orphanedSince[subnet]
+	current := make(map[string]time.Time, len(orphaned))
+
+	var expired []pcclient.ReservedIP
+	for _, reservedIP := range orphaned {
+		key := orphanedReservationKey(reservedIP)
+		since, ok := previous[key]
+		if !ok {
+			since = now
+		}
+		current[key] = since
+
+		if now.Sub(since) >= r.opts.reservationGCGracePeriod {
+			expired = append(expired, reservedIP)
+		}
+	}
+	r.orphanedSince[subnet] = current
+
+	return expired
+}
+
+func (r *ReservationGCReconciler) forgetOrphaned(subnet string, reservedIP pcclient.ReservedIP) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.orphanedSince[subnet], orphanedReservationKey(reservedIP))
+}
+
+func orphanedReservationKey(reservedIP pcclient.ReservedIP) string {
+	return reservedIP.ClientContext + "/" + reservedIP.Address.String()
+}
+
+func (r *ReservationGCReconciler) getPool(
+	ctx context.Context,
+	kind string,
+	key types.NamespacedName,
+) (genericNutanixIPPool, error) {
+	var pool genericNutanixIPPool
+	switch kind {
+	case v1alpha1.GlobalNutanixIPPoolKind:
+		pool = &v1alpha1.GlobalNutanixIPPool{}
+	default:
+		pool = &v1alpha1.NutanixIPPool{}
+	}
+	if err := r.client.Get(ctx, key, pool); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// claimClientContextsForSubnets returns the client contexts and the UIDs of the claims of all pools sharing a
+// subnet with the given pool. Subnet extIDs are unique across Prism Centrals, so pools are compared by subnet
+// extID only, regardless of how they address their Prism Central. As subnets can only be compared once resolved,
+// false is returned if any pool has not resolved its subnets yet.
+func (r *ReservationGCReconciler) claimClientContextsForSubnets(
+	ctx context.Context,
+	pool genericNutanixIPPool,
+) (sets.Set[string], bool, error) {
+	subnets := sets.New(poolSubnetExtIDs(pool)...)
+
+	pools := &v1alpha1.NutanixIPPoolList{}
+	if err := r.client.List(ctx, pools); err != nil {
+		return nil, false, fmt.Errorf("failed to list NutanixIPPools: %w", err)
+	}
+	globalPools := &v1alpha1.GlobalNutanixIPPoolList{}
+	if err := r.client.List(ctx, globalPools); err != nil {
+		return nil, false, fmt.Errorf("failed to list GlobalNutanixIPPools: %w", err)
+	}
+
+	type poolRef struct {
+		kind      string
+		namespace string
+		name      string
+	}
+	var sharing []poolRef
+	addIfSharing := func(kind string, other genericNutanixIPPool) bool {
+		otherSubnets := poolSubnetExtIDs(other)
+		if len(otherSubnets) == 0 {
+			return false
+		}
+		if subnets.HasAny(otherSubnets...) {
+			sharing = append(sharing, poolRef{kind: kind, namespace: other.GetNamespace(), name: other.GetName()})
+		}
+		return true
+	}
+	for i := range pools.Items {
+		if !addIfSharing(v1alpha1.NutanixIPPoolKind, &pools.Items[i]) {
+			return nil, false, nil
+		}
+	}
+	for i := range globalPools.Items {
+		if !addIfSharing(v1alpha1.GlobalNutanixIPPoolKind, &globalPools.Items[i]) {
+			return nil, false, nil
+		}
+	}
+
+	clientContexts := sets.New[string]()
+	for _, ref := range sharing {
+		claims := &ipamv1.IPAddressClaimList{}
+		if err := r.client.List(ctx, claims,
+			ctrlclient.InNamespace(ref.namespace),
+			ctrlclient.MatchingFields{
+				index.IPAddressClaimPoolRefCombinedField: index.IPPoolRefValue(ipamv1.IPPoolReference{
+					Kind: ref.kind,
+					Name: ref.name,
+				}),
+			},
+		); err != nil {
+			return nil, false, fmt.Errorf("failed to list IPAddressClaims for %s %s: %w", ref.kind, ref.name, err)
+		}
+		for i := range claims.Items {
+			clientContexts.Insert(string(claims.Items[i].UID), claimClientContext(&claims.Items[i]))
+		}
+	}
+
+	return clientContexts, true, nil
+}
+
+// poolSubnetExtIDs returns the extIDs of the subnets resolved in the pool status.
+func poolSubnetExtIDs(pool genericNutanixIPPool) []string {
+	var subnets []string
+	status := pool.PoolStatus()
+	if status.Subnet != nil {
+		subnets = append(subnets, status.Subnet.ExtID)
+	}
+	if status.IPv6Subnet != nil {
+		subnets = append(subnets, status.IPv6Subnet.ExtID)
+	}
+	for _, fallbackSubnet := range status.FallbackSubnets {
+		subnets = append(subnets, fallbackSubnet.ExtID)
+	}
+	return subnets
+}
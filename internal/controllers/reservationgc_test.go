@@ -0,0 +1,316 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ipamv1 "sigs.k8s.io/cluster-api/api/ipam/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/nutanix-cloud-native/prism-go-client/environment/credentials"
+
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
+	pcclient "github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/client"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/controllers/mockclient"
+)
+
+var _ = Describe("ReservationGCReconciler", func() {
+	const (
+		poolName            = "test-pool"
+		clientContextPrefix = "caipamx-gc/"
+	)
+
+	var (
+		namespace    string
+		subnetExtID  string
+		pool         v1alpha1.NutanixIPPool
+		claim        ipamv1.IPAddressClaim
+		poolPCClient *mockclient.MockClient
+		mockNC       *mockclient.MockNetworkingClient
+		recorder     *events.FakeRecorder
+		now          time.Time
+		reconciler   *ReservationGCReconciler
+	)
+
+	BeforeEach(func() {
+		ns, err := env.CreateNamespace(context.Background(), "test-ns")
+		Expect(err).NotTo(HaveOccurred())
+		namespace = ns.Name
+
+		mockController = gomock.NewController(GinkgoT())
+		DeferCleanup(func() {
+			Expect(mockController.Satisfied()).To(BeTrue())
+		})
+		DeferCleanup(mockController.Finish)
+
+		poolPCClient = mockclient.NewMockClient(mockController)
+		mockNC = mockclient.NewMockNetworkingClient(mockController)
+		poolPCClient.EXPECT().Networking().Return(mockNC).AnyTimes()
+
+		recorder = events.NewFakeRecorder(10)
+		now = time.Now()
+		reconciler = NewReservationGCReconciler(env, "", secretInformer, nil, recorder, DefaultReconcilerOptions())
+		reconciler.opts.reservationGCGracePeriod = 0
+		reconciler.opts.reservationGCClientContextPrefix = clientContextPrefix
+		reconciler.pcClientGetter = func(_ pcclient.CachedClientParams) (pcclient.Client, error) {
+			return poolPCClient, nil
+		}
+		reconciler.now = func() time.Time {
+			return now
+		}
+
+		secret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-secret",
+				Namespace: namespace,
+			},
+			StringData: map[string]string{
+				credentials.KeyName: `
+		[
+		  {
+		    "type": "basic_auth",
+		    "data": {
+		      "prismCentral":{
+		        "username": "auser",
+		        "password": "apassword"
+		      }
+		    }
+		  }
+		]`,
+			},
+		}
+		Expect(env.CreateAndWait(context.Background(), &secret)).To(Succeed())
+
+		subnetExtID = uuid.NewString()
+		pool = v1alpha1.NutanixIPPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      poolName,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.NutanixIPPoolSpec{
+				PrismCentral: v1alpha1.PrismCentral{
+					Address: "prism.example.com",
+					Port:    9440,
+					CredentialsSecretRef: v1alpha1.LocalSecretRef{
+						Name: "test-secret",
+					},
+				},
+				Subnet:                subnetExtID,
+				ClientContextTemplate: ptr.To(clientContextPrefix + "{{ .UID }}"),
+			},
+		}
+		Expect(env.CreateAndWait(context.Background(), &pool)).To(Succeed())
+		DeferCleanup(env.CleanupAndWait, context.Background(), &pool, &secret)
+
+		// Mark the pool as not ready so that the claim reconciler does not try to allocate an IP for the claim.
+		pool.Status.Subnet = &v1alpha1.NutanixIPPoolStatusSubnet{ExtID: subnetExtID, Prefix: 24}
+		conditions.Set(&pool, metav1.Condition{
+			Type:   v1alpha1.NutanixIPPoolReadyCondition,
+			Status: metav1.ConditionFalse,
+			Reason: v1alpha1.NutanixIPPoolSubnetResolutionFailedReason,
+		})
+		Expect(env.Status().Update(context.Background(), &pool)).To(Succeed())
+		Eventually(func(g Gomega) *v1alpha1.NutanixIPPoolStatusSubnet {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &pool)).To(Succeed())
+			return pool.Status.Subnet
+		}).ShouldNot(BeNil())
+
+		claim = newClaim("test", namespace, v1alpha1.NutanixIPPoolKind, poolName)
+		Expect(env.CreateAndWait(context.Background(), &claim)).To(Succeed())
+		DeferCleanup(env.CleanupAndWait, context.Background(), &claim)
+	})
+
+	reconcileGC := func() (ctrl.Result, error) {
+		return reconciler.reconcile(context.Background(), ctrl.Request{
+			NamespacedName: client.ObjectKeyFromObject(&pool),
+		}, v1alpha1.NutanixIPPoolKind)
+	}
+
+	It("should release reservations of claims that no longer exist", func() {
+		orphanedContext := clientContextPrefix + uuid.NewString()
+		mockNC.EXPECT().ListReservedIPs(gomock.Any(), subnetExtID, gomock.Any()).Return([]pcclient.ReservedIP{
+			{Address: netip.MustParseAddr("10.0.0.10"), ClientContext: clientContextPrefix + string(claim.UID)},
+			{Address: netip.MustParseAddr("10.0.0.11"), ClientContext: orphanedContext},
+			{Address: netip.MustParseAddr("10.0.0.12"), ClientContext: "not-managed-by-caipamx"},
+			{Address: netip.MustParseAddr("10.0.0.13")},
+		}, nil)
+		mockNC.EXPECT().UnreserveIPs(gomock.Any(), gomock.Any(), subnetExtID, gomock.Any()).Return(
+			[]netip.Addr{netip.MustParseAddr("10.0.0.11")}, nil,
+		)
+
+		result, err := reconcileGC()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(reconciler.opts.reservationGCInterval))
+
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring(ReservationReleasedReason),
+			ContainSubstring("10.0.0.11"),
+			ContainSubstring(orphanedContext),
+		)))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should release orphaned reservations with a client context rendered from the pool template", func() {
+		poolPatch := client.MergeFrom(pool.DeepCopy())
+		pool.Spec.ClientContextTemplate = ptr.To(clientContextPrefix + "{{ .Namespace }}/{{ .Name }}/{{ .UID }}")
+		Expect(env.Patch(context.Background(), &pool, poolPatch)).To(Succeed())
+		Eventually(func(g Gomega) *string {
+			cached := v1alpha1.NutanixIPPool{}
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&pool), &cached)).To(Succeed())
+			return cached.Spec.ClientContextTemplate
+		}).Should(Equal(pool.Spec.ClientContextTemplate))
+
+		orphanedContext := clientContextPrefix + namespace + "/deleted/" + uuid.NewString()
+		mockNC.EXPECT().ListReservedIPs(gomock.Any(), subnetExtID, gomock.Any()).Return([]pcclient.ReservedIP{
+			{
+				Address:       netip.MustParseAddr("10.0.0.10"),
+				ClientContext: clientContextPrefix + namespace + "/test/" + string(claim.UID),
+			},
+			{Address: netip.MustParseAddr("10.0.0.11"), ClientContext: orphanedContext},
+			{Address: netip.MustParseAddr("10.0.0.12"), ClientContext: "other/template/" + uuid.NewString() + "/x"},
+		}, nil)
+		mockNC.EXPECT().UnreserveIPs(gomock.Any(), gomock.Any(), subnetExtID, gomock.Any()).Return(
+			[]netip.Addr{netip.MustParseAddr("10.0.0.11")}, nil,
+		)
+
+		_, err := reconcileGC()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring(ReservationReleasedReason),
+			ContainSubstring("10.0.0.11"),
+			ContainSubstring(orphanedContext),
+		)))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should only report orphaned reservations not owned by the management cluster", func() {
+		mockNC.EXPECT().ListReservedIPs(gomock.Any(), subnetExtID, gomock.Any()).Return([]pcclient.ReservedIP{
+			{Address: netip.MustParseAddr("10.0.0.11"), ClientContext: uuid.NewString()},
+			{Address: netip.MustParseAddr("10.0.0.12"), ClientContext: "other-mgmt/" + uuid.NewString()},
+		}, nil)
+
+		_, err := reconcileGC()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring(OrphanedReservationReason),
+			ContainSubstring("10.0.0.11"),
+		)))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should not release any reservation without a client context prefix", func() {
+		reconciler.opts.reservationGCClientContextPrefix = ""
+
+		mockNC.EXPECT().ListReservedIPs(gomock.Any(), subnetExtID, gomock.Any()).Return([]pcclient.ReservedIP{
+			{Address: netip.MustParseAddr("10.0.0.11"), ClientContext: clientContextPrefix + uuid.NewString()},
+		}, nil)
+
+		_, err := reconcileGC()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring(OrphanedReservationReason),
+			ContainSubstring("10.0.0.11"),
+		)))
+	})
+
+	It("should keep reservations of claims of pools addressing the same Prism Central differently", func() {
+		otherPool := v1alpha1.NutanixIPPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-pool",
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.NutanixIPPoolSpec{
+				PrismCentral: v1alpha1.PrismCentral{
+					Address: "prism.example.com.",
+					Port:    9440,
+					CredentialsSecretRef: v1alpha1.LocalSecretRef{
+						Name: "test-secret",
+					},
+				},
+				Subnet: subnetExtID,
+			},
+		}
+		Expect(env.CreateAndWait(context.Background(), &otherPool)).To(Succeed())
+		DeferCleanup(env.CleanupAndWait, context.Background(), &otherPool)
+		otherPool.Status.Subnet = &v1alpha1.NutanixIPPoolStatusSubnet{ExtID: subnetExtID, Prefix: 24}
+		conditions.Set(&otherPool, metav1.Condition{
+			Type:   v1alpha1.NutanixIPPoolReadyCondition,
+			Status: metav1.ConditionFalse,
+			Reason: v1alpha1.NutanixIPPoolSubnetResolutionFailedReason,
+		})
+		Expect(env.Status().Update(context.Background(), &otherPool)).To(Succeed())
+		Eventually(func(g Gomega) *v1alpha1.NutanixIPPoolStatusSubnet {
+			g.Expect(env.Get(context.Background(), client.ObjectKeyFromObject(&otherPool), &otherPool)).To(Succeed())
+			return otherPool.Status.Subnet
+		}).ShouldNot(BeNil())
+
+		otherClaim := newClaim("other", namespace, v1alpha1.NutanixIPPoolKind, otherPool.Name)
+		Expect(env.CreateAndWait(context.Background(), &otherClaim)).To(Succeed())
+		DeferCleanup(env.CleanupAndWait, context.Background(), &otherClaim)
+
+		mockNC.EXPECT().ListReservedIPs(gomock.Any(), subnetExtID, gomock.Any()).Return([]pcclient.ReservedIP{
+			{Address: netip.MustParseAddr("10.0.0.11"), ClientContext: clientContextPrefix + string(otherClaim.UID)},
+		}, nil)
+
+		_, err := reconcileGC()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should only report orphaned reservations in dry-run mode", func() {
+		reconciler.opts.reservationGCDryRun = true
+
+		mockNC.EXPECT().ListReservedIPs(gomock.Any(), subnetExtID, gomock.Any()).Return([]pcclient.ReservedIP{
+			{Address: netip.MustParseAddr("10.0.0.11"), ClientContext: clientContextPrefix + uuid.NewString()},
+		}, nil)
+
+		_, err := reconcileGC()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring(OrphanedReservationReason),
+			ContainSubstring("10.0.0.11"),
+		)))
+	})
+
+	It("should wait for the grace period before releasing orphaned reservations", func() {
+		reconciler.opts.reservationGCGracePeriod = time.Hour
+
+		orphaned := []pcclient.ReservedIP{
+			{Address: netip.MustParseAddr("10.0.0.11"), ClientContext: clientContextPrefix + uuid.NewString()},
+		}
+		gomock.InOrder(
+			mockNC.EXPECT().ListReservedIPs(gomock.Any(), subnetExtID, gomock.Any()).Return(orphaned, nil),
+			mockNC.EXPECT().ListReservedIPs(gomock.Any(), subnetExtID, gomock.Any()).Return(orphaned, nil),
+			mockNC.EXPECT().UnreserveIPs(gomock.Any(), gomock.Any(), subnetExtID, gomock.Any()).Return(
+				[]netip.Addr{netip.MustParseAddr("10.0.0.11")}, nil,
+			),
+		)
+
+		_, err := reconcileGC()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		now = now.Add(time.Hour)
+		_, err = reconcileGC()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring(ReservationReleasedReason)))
+	})
+})
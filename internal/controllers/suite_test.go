@@ -13,8 +13,10 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	clientgocache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/cluster-api-ipam-provider-in-cluster/pkg/ipamutil"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -29,6 +31,10 @@ var (
 	env            *envtest.Environment
 	mockController *gomock.Controller
 	mockPCClient   *mockclient.MockClient
+	secretInformer coreinformers.SecretInformer
+	// claimEventRecorder records the events of the IPAddressClaim reconciler. The buffer must be large enough
+	// for the events emitted by all tests, as the fake recorder blocks when the buffer is full.
+	claimEventRecorder = events.NewFakeRecorder(1000)
 )
 
 func TestMain(m *testing.M) {
@@ -38,7 +44,7 @@ func TestMain(m *testing.M) {
 		clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
 		Expect(err).NotTo(HaveOccurred())
 		informerFactory := informers.NewSharedInformerFactory(clientset, time.Minute)
-		secretInformer := informerFactory.Core().V1().Secrets()
+		secretInformer = informerFactory.Core().V1().Secrets()
 		informer := secretInformer.Informer()
 		go informer.Run(ctx.Done())
 		Expect(clientgocache.WaitForCacheSync(ctx.Done(), informer.HasSynced)).To(BeTrue())
@@ -51,6 +57,7 @@ func TestMain(m *testing.M) {
 				Adapter: &NutanixProviderAdapter{
 					k8sClient:      mgr.GetClient(),
 					secretInformer: secretInformer,
+					recorder:       claimEventRecorder,
 					pcClientGetter: func(_ client.CachedClientParams) (client.Client, error) {
 						return mockPCClient, nil
 					},
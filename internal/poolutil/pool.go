@@ -7,6 +7,8 @@ package poolutil
 import (
 	"fmt"
 	"math/big"
+	"net/netip"
+	"strings"
 
 	"go4.org/netipx"
 )
@@ -42,3 +44,77 @@ func IPSetCount(ipSet *netipx.IPSet) (int64, error) {
 
 	return 0, fmt.Errorf("IPSet count is too large to fit in an int64")
 }
+
+// AddressToIPRange parses an address given as a CIDR (e.g. 10.0.0.0/24), an IP range (e.g. 10.0.0.10-10.0.0.20)
+// or a single IP into an IP range. The network and broadcast addresses of a CIDR are included in the range.
+func AddressToIPRange(address string) (netipx.IPRange, error) {
+	switch {
+	case strings.Contains(address, "/"):
+		prefix, err := netip.ParsePrefix(address)
+		if err != nil {
+			return netipx.IPRange{}, fmt.Errorf("invalid CIDR %q: %w", address, err)
+		}
+		return netipx.RangeOfPrefix(prefix.Masked()), nil
+	case strings.Contains(address, "-"):
+		ipRange, err := netipx.ParseIPRange(address)
+		if err != nil {
+			return netipx.IPRange{}, fmt.Errorf("invalid IP range %q: %w", address, err)
+		}
+		return ipRange, nil
+	default:
+		addr, err := netip.ParseAddr(address)
+		if err != nil {
+			return netipx.IPRange{}, fmt.Errorf("invalid IP %q: %w", address, err)
+		}
+		return netipx.IPRangeFrom(addr, addr), nil
+	}
+}
+
+// AddressesToIPSet parses a list of addresses given as CIDRs, IP ranges or single IPs into an IPSet. A nil
+// IPSet is returned if the list is empty.
+func AddressesToIPSet(addresses []string) (*netipx.IPSet, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	var builder netipx.IPSetBuilder
+	for _, address := range addresses {
+		ipRange, err := AddressToIPRange(address)
+		if err != nil {
+			return nil, err
+		}
+		builder.AddRange(ipRange)
+	}
+
+	return builder.IPSet()
+}
+
+// FreeIPSet returns the IPs of available that are contained in allowed and not contained in any of the excluded
+// IPSets. A nil allowed IPSet allows all IPs of available.
+func FreeIPSet(available, allowed *netipx.IPSet, excluded ...*netipx.IPSet) (*netipx.IPSet, error) {
+	var builder netipx.IPSetBuilder
+	builder.AddSet(available)
+	if allowed != nil {
+		builder.Intersect(allowed)
+	}
+	for _, e := range excluded {
+		if e != nil {
+			builder.RemoveSet(e)
+		}
+	}
+
+	return builder.IPSet()
+}
+
+// FirstIP returns the lowest IP of the given IPSet. The returned IP is invalid if the IPSet is empty.
+func FirstIP(ipSet *netipx.IPSet) netip.Addr {
+	if ipSet == nil {
+		return netip.Addr{}
+	}
+
+	ranges := ipSet.Ranges()
+	if len(ranges) == 0 {
+		return netip.Addr{}
+	}
+	return ranges[0].From()
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package fakepc
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// cluster is a Prism Element cluster registered with the fake Prism Central.
+type cluster struct {
+	extID uuid.UUID
+	name  string
+}
+
+// apiCluster is the clustermgmt v4 representation of a cluster, limited to the properties used by the client.
+type apiCluster struct {
+	ObjectType string `json:"$objectType"`
+	ExtID      string `json:"extId"`
+	Name       string `json:"name"`
+}
+
+func (c *cluster) api() apiCluster {
+	return apiCluster{
+		ObjectType: "clustermgmt.v4.config.Cluster",
+		ExtID:      c.extID.String(),
+		Name:       c.name,
+	}
+}
+
+// AddCluster registers a cluster with the name and returns its extID. Names need not be unique.
+func (s *Server) AddCluster(name string) uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	extID := uuid.New()
+	s.clusters[extID] = &cluster{extID: extID, name: name}
+	return extID
+}
+
+func (s *Server) listClusters(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilterQuery(w, r, "name", "extId")
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	var clusters []apiCluster
+	for _, c := range s.clusters {
+		if filter.matches(map[string]string{"name": c.name, "extId": c.extID.String()}) {
+			clusters = append(clusters, c.api())
+		}
+	}
+	s.mu.Unlock()
+	slices.SortFunc(clusters, func(a, b apiCluster) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ExtID, b.ExtID))
+	})
+
+	total := len(clusters)
+	clusters, ok = page(w, r, clusters)
+	if !ok {
+		return
+	}
+	response := map[string]any{
+		"metadata": map[string]any{"totalAvailableResults": total},
+	}
+	// Prism Central omits the data of an empty list.
+	if len(clusters) > 0 {
+		response["data"] = clusters
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getCluster(w http.ResponseWriter, r *http.Request) {
+	extID, ok := parseExtID(w, r, "cluster")
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	c, ok := s.clusters[extID]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "CLU-404", "cluster %q not found", extID)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": c.api()})
+}
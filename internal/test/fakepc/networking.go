@@ -0,0 +1,460 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package fakepc
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/netip"
+	"slices"
+
+	"github.com/google/uuid"
+	commonapi "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/common/v1/config"
+	responseapi "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/common/v1/response"
+	networkingapi "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/networking/v4/config"
+	prismapi "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/prism/v4/config"
+	"go4.org/netipx"
+	"k8s.io/utils/ptr"
+)
+
+// Subnet is the configuration of an IPv4 subnet added with AddSubnet.
+type Subnet struct {
+	// Name is the name of the subnet. Names need not be unique.
+	Name string
+	// Cluster is the extID of the cluster the subnet belongs to, if any.
+	Cluster uuid.UUID
+	// Prefix is the IP prefix of the subnet, e.g. 10.0.0.0/24.
+	Prefix netip.Prefix
+	// Gateway is the default gateway of the subnet, if any.
+	Gateway netip.Addr
+	// Pools are the IP pools of the subnet, out of which IP addresses are reserved by count.
+	Pools []netipx.IPRange
+	// DNSServers, SearchDomains and DomainName are the DHCP options of the subnet.
+	DNSServers    []netip.Addr
+	SearchDomains []string
+	DomainName    string
+}
+
+// subnet is a subnet of the fake Prism Central along with its reserved IP addresses.
+type subnet struct {
+	spec  Subnet
+	extID uuid.UUID
+	// reserved maps the reserved IP addresses to the client context they were reserved with.
+	reserved map[netip.Addr]string
+}
+
+// AddSubnet adds a subnet and returns its extID.
+func (s *Server) AddSubnet(spec Subnet) uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	extID := uuid.New()
+	s.subnets[extID] = &subnet{spec: spec, extID: extID, reserved: map[netip.Addr]string{}}
+	return extID
+}
+
+// ReservedIPs returns the IP addresses reserved in the subnet, mapped to the client context they were reserved with.
+func (s *Server) ReservedIPs(subnetExtID uuid.UUID) map[netip.Addr]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subnets[subnetExtID]
+	if !ok {
+		return nil
+	}
+	return maps.Clone(sub.reserved)
+}
+
+// ReserveIPs reserves the IP addresses in the subnet with the client context, as if they had been reserved by
+// another client. An error is returned if the subnet does not exist or an IP address is already reserved.
+func (s *Server) ReserveIPs(subnetExtID uuid.UUID, clientContext string, addrs ...netip.Addr) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subnets[subnetExtID]
+	if !ok {
+		return fmt.Errorf("subnet %s not found", subnetExtID)
+	}
+	if err := sub.reserve(addrs, clientContext); err != nil {
+		return fmt.Errorf("failed to reserve IP addresses in subnet %s: %s", subnetExtID, err.message)
+	}
+	return nil
+}
+
+// apiError is an error returned by the API with a status code and a message.
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func newAPIError(status int, code, format string, args ...any) *apiError {
+	return &apiError{status: status, code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func (e *apiError) write(w http.ResponseWriter) {
+	writeError(w, e.status, e.code, "%s", e.message)
+}
+
+// api returns the networking v4 representation of the subnet.
+func (sub *subnet) api() networkingapi.Subnet {
+	spec := sub.spec
+
+	ipSubnet := networkingapi.NewIPv4Subnet()
+	ipSubnet.Ip = ipv4Address(spec.Prefix.Masked().Addr())
+	ipSubnet.PrefixLength = ptr.To(spec.Prefix.Bits())
+
+	ipv4Config := networkingapi.NewIPv4Config()
+	ipv4Config.IpSubnet = ipSubnet
+	if spec.Gateway.IsValid() {
+		ipv4Config.DefaultGatewayIp = ipv4Address(spec.Gateway)
+	}
+	for _, pool := range spec.Pools {
+		apiPool := networkingapi.NewIPv4Pool()
+		apiPool.StartIp = ipv4Address(pool.From())
+		apiPool.EndIp = ipv4Address(pool.To())
+		ipv4Config.PoolList = append(ipv4Config.PoolList, *apiPool)
+	}
+	ipConfig := networkingapi.NewIPConfig()
+	ipConfig.Ipv4 = ipv4Config
+
+	dhcpOptions := networkingapi.NewDhcpOptions()
+	for _, server := range spec.DNSServers {
+		address := commonapi.NewIPAddress()
+		address.Ipv4 = ipv4Address(server)
+		dhcpOptions.DomainNameServers = append(dhcpOptions.DomainNameServers, *address)
+	}
+	dhcpOptions.SearchDomains = spec.SearchDomains
+	if spec.DomainName != "" {
+		dhcpOptions.DomainName = ptr.To(spec.DomainName)
+	}
+
+	var free int64
+	for _, pool := range spec.Pools {
+		for addr := pool.From(); pool.Contains(addr); addr = addr.Next() {
+			if _, ok := sub.reserved[addr]; !ok {
+				free++
+			}
+		}
+	}
+	ipUsage := networkingapi.NewIPUsage()
+	ipUsage.NumAssignedIPs = ptr.To(int64(len(sub.reserved)))
+	ipUsage.NumFreeIPs = ptr.To(free)
+
+	apiSubnet := networkingapi.NewSubnet()
+	apiSubnet.ExtId = ptr.To(sub.extID.String())
+	apiSubnet.Name = ptr.To(spec.Name)
+	if spec.Cluster != uuid.Nil {
+		apiSubnet.ClusterReference = ptr.To(spec.Cluster.String())
+	}
+	apiSubnet.IpConfig = []networkingapi.IPConfig{*ipConfig}
+	apiSubnet.DhcpOptions = dhcpOptions
+	apiSubnet.IpUsage = ipUsage
+	return *apiSubnet
+}
+
+func ipv4Address(addr netip.Addr) *commonapi.IPv4Address {
+	address := commonapi.NewIPv4Address()
+	address.Value = ptr.To(addr.String())
+	address.PrefixLength = ptr.To(32)
+	return address
+}
+
+// reserve reserves the IP addresses with the client context. No IP address is reserved if any of them is outside
+// the subnet or already reserved.
+func (sub *subnet) reserve(addrs []netip.Addr, clientContext string) *apiError {
+	for _, addr := range addrs {
+		if !sub.spec.Prefix.Contains(addr) {
+			return newAPIError(
+				http.StatusBadRequest, "NET-400", "IP address %s is not in subnet %s", addr, sub.spec.Prefix,
+			)
+		}
+		if _, ok := sub.reserved[addr]; ok {
+			return newAPIError(http.StatusConflict, "NET-409", "IP address %s is already reserved", addr)
+		}
+	}
+	for _, addr := range addrs {
+		sub.reserved[addr] = clientContext
+	}
+	return nil
+}
+
+// free returns the first count free IP addresses of the IP pools of the subnet.
+func (sub *subnet) free(count int64) ([]netip.Addr, *apiError) {
+	var addrs []netip.Addr
+	for _, pool := range sub.spec.Pools {
+		for addr := pool.From(); pool.Contains(addr) && int64(len(addrs)) < count; addr = addr.Next() {
+			if _, ok := sub.reserved[addr]; !ok {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	if int64(len(addrs)) < count {
+		return nil, newAPIError(
+			http.StatusBadRequest,
+			"NET-400",
+			"Not enough free IPs in the IP pools of subnet %s to reserve %d IPs",
+			sub.extID,
+			count,
+		)
+	}
+	return addrs, nil
+}
+
+// addressRange returns count IP addresses starting at the start IP address.
+func addressRange(start *commonapi.IPAddress, count *int64) ([]netip.Addr, *apiError) {
+	addrs, err := addressList([]commonapi.IPAddress{ptr.Deref(start, commonapi.IPAddress{})})
+	if err != nil {
+		return nil, err
+	}
+	if ptr.Deref(count, 0) <= 0 {
+		return nil, newAPIError(http.StatusBadRequest, "NET-400", "count must be positive")
+	}
+	for addr := addrs[0].Next(); int64(len(addrs)) < *count; addr = addr.Next() {
+		if !addr.IsValid() {
+			return nil, newAPIError(http.StatusBadRequest, "NET-400", "IP address range overflows")
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// addressList parses the IPv4 addresses.
+func addressList(addresses []commonapi.IPAddress) ([]netip.Addr, *apiError) {
+	if len(addresses) == 0 {
+		return nil, newAPIError(http.StatusBadRequest, "NET-400", "no IP addresses specified")
+	}
+	addrs := make([]netip.Addr, 0, len(addresses))
+	for _, address := range addresses {
+		if address.Ipv4 == nil {
+			return nil, newAPIError(http.StatusBadRequest, "NET-400", "only IPv4 addresses are supported")
+		}
+		addr, err := netip.ParseAddr(ptr.Deref(address.Ipv4.Value, ""))
+		if err != nil || !addr.Is4() {
+			return nil, newAPIError(
+				http.StatusBadRequest, "NET-400", "invalid IPv4 address %q", ptr.Deref(address.Ipv4.Value, ""),
+			)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+func (s *Server) listSubnets(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilterQuery(w, r, "name", "extId", "clusterReference")
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	var subnets []networkingapi.Subnet
+	for _, sub := range s.subnets {
+		properties := map[string]string{"name": sub.spec.Name, "extId": sub.extID.String()}
+		if sub.spec.Cluster != uuid.Nil {
+			properties["clusterReference"] = sub.spec.Cluster.String()
+		}
+		if filter.matches(properties) {
+			subnets = append(subnets, sub.api())
+		}
+	}
+	s.mu.Unlock()
+	slices.SortFunc(subnets, func(a, b networkingapi.Subnet) int {
+		return cmp.Or(cmp.Compare(*a.Name, *b.Name), cmp.Compare(*a.ExtId, *b.ExtId))
+	})
+
+	total := len(subnets)
+	subnets, ok = page(w, r, subnets)
+	if !ok {
+		return
+	}
+	response := networkingapi.NewListSubnetsApiResponse()
+	response.Metadata = listMetadata(total)
+	if len(subnets) > 0 {
+		if err := response.SetData(subnets); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getSubnet(w http.ResponseWriter, r *http.Request) {
+	s.withSubnet(w, r, func(sub *subnet) {
+		response := networkingapi.NewGetSubnetApiResponse()
+		if err := response.SetData(sub.api()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+}
+
+func (s *Server) listReservedIPs(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseFilterQuery(w, r, "clientContext")
+	if !ok {
+		return
+	}
+
+	s.withSubnet(w, r, func(sub *subnet) {
+		var reservedIPs []networkingapi.ReservedIp
+		for _, addr := range slices.SortedFunc(maps.Keys(sub.reserved), netip.Addr.Compare) {
+			if !filter.matches(map[string]string{"clientContext": sub.reserved[addr]}) {
+				continue
+			}
+			reservedIP := networkingapi.NewReservedIp()
+			reservedIP.Ipv4Address = ptr.To(addr.String())
+			if sub.reserved[addr] != "" {
+				reservedIP.ClientContext = ptr.To(sub.reserved[addr])
+			}
+			reservedIPs = append(reservedIPs, *reservedIP)
+		}
+
+		total := len(reservedIPs)
+		reservedIPs, ok := page(w, r, reservedIPs)
+		if !ok {
+			return
+		}
+		response := networkingapi.NewListSubnetReservedIpsApiResponse()
+		response.Metadata = listMetadata(total)
+		if len(reservedIPs) > 0 {
+			if err := response.SetData(reservedIPs); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+}
+
+func (s *Server) reserveIPs(w http.ResponseWriter, r *http.Request) {
+	var spec networkingapi.IpReserveSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		writeError(w, http.StatusBadRequest, "NET-400", "invalid reservation: %v", err)
+		return
+	}
+
+	s.withSubnet(w, r, func(sub *subnet) {
+		var (
+			addrs  []netip.Addr
+			apiErr *apiError
+		)
+		switch ptr.Deref(spec.ReserveType, networkingapi.RESERVETYPE_UNKNOWN) {
+		case networkingapi.RESERVETYPE_IP_ADDRESS_COUNT:
+			if ptr.Deref(spec.Count, 0) <= 0 {
+				apiErr = newAPIError(http.StatusBadRequest, "NET-400", "count must be positive")
+				break
+			}
+			addrs, apiErr = sub.free(*spec.Count)
+		case networkingapi.RESERVETYPE_IP_ADDRESS_RANGE:
+			addrs, apiErr = addressRange(spec.StartIpAddress, spec.Count)
+		case networkingapi.RESERVETYPE_IP_ADDRESS_LIST:
+			addrs, apiErr = addressList(spec.IpAddresses)
+		default:
+			apiErr = newAPIError(http.StatusBadRequest, "NET-400", "unsupported reserve type")
+		}
+		if apiErr == nil {
+			apiErr = sub.reserve(addrs, ptr.Deref(spec.ClientContext, ""))
+		}
+		if apiErr != nil {
+			apiErr.write(w)
+			return
+		}
+		s.writeTask(w, sub, ReservedIPsCompletionDetail, addrs)
+	})
+}
+
+func (s *Server) unreserveIPs(w http.ResponseWriter, r *http.Request) {
+	var spec networkingapi.IpUnreserveSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		writeError(w, http.StatusBadRequest, "NET-400", "invalid unreservation: %v", err)
+		return
+	}
+
+	s.withSubnet(w, r, func(sub *subnet) {
+		var (
+			addrs  []netip.Addr
+			apiErr *apiError
+		)
+		switch ptr.Deref(spec.UnreserveType, networkingapi.UNRESERVETYPE_UNKNOWN) {
+		case networkingapi.UNRESERVETYPE_CONTEXT:
+			clientContext := ptr.Deref(spec.ClientContext, "")
+			for addr, reservedContext := range sub.reserved {
+				if clientContext != "" && reservedContext == clientContext {
+					addrs = append(addrs, addr)
+				}
+			}
+			if len(addrs) == 0 {
+				apiErr = newAPIError(
+					http.StatusBadRequest, "NET-400", "No IP addresses exist with context %s", clientContext,
+				)
+			}
+		case networkingapi.UNRESERVETYPE_IP_ADDRESS_RANGE:
+			addrs, apiErr = addressRange(spec.StartIpAddress, spec.Count)
+		case networkingapi.UNRESERVETYPE_IP_ADDRESS_LIST:
+			addrs, apiErr = addressList(spec.IpAddresses)
+		default:
+			apiErr = newAPIError(http.StatusBadRequest, "NET-400", "unsupported unreserve type")
+		}
+		if apiErr != nil {
+			apiErr.write(w)
+			return
+		}
+
+		// IP addresses that are not reserved are ignored.
+		var unreserved []netip.Addr
+		for _, addr := range addrs {
+			if _, ok := sub.reserved[addr]; ok {
+				delete(sub.reserved, addr)
+				unreserved = append(unreserved, addr)
+			}
+		}
+		slices.SortFunc(unreserved, netip.Addr.Compare)
+		s.writeTask(w, sub, UnreservedIPsCompletionDetail, unreserved)
+	})
+}
+
+// withSubnet calls the handler with the subnet of the extId path parameter while holding the lock of the server,
+// writing a not found error if the subnet does not exist.
+func (s *Server) withSubnet(w http.ResponseWriter, r *http.Request, handler func(sub *subnet)) {
+	extID, ok := parseExtID(w, r, "subnet")
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subnets[extID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NET-404", "subnet %q not found", extID)
+		return
+	}
+	handler(sub)
+}
+
+// writeTask starts a task of the subnet completing with the IP addresses and writes a reference to it. The lock of
+// the server must be held.
+func (s *Server) writeTask(w http.ResponseWriter, sub *subnet, completionDetail string, addrs []netip.Addr) {
+	extID := uuid.New()
+	s.tasks[extID] = &task{
+		extID:            extID,
+		subnetExtID:      sub.extID,
+		completionDetail: completionDetail,
+		addrs:            addrs,
+	}
+
+	taskReference := prismapi.NewTaskReference()
+	taskReference.ExtId = ptr.To(extID.String())
+	response := networkingapi.NewTaskReferenceApiResponse()
+	if err := response.SetData(*taskReference); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, response)
+}
+
+func listMetadata(total int) *responseapi.ApiResponseMetadata {
+	metadata := responseapi.NewApiResponseMetadata()
+	metadata.TotalAvailableResults = ptr.To(total)
+	return metadata
+}
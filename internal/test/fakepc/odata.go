@@ -0,0 +1,93 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package fakepc
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// filter is a parsed OData $filter expression out of string comparisons joined with "and", the only form sent by
+// the client.
+type filter map[string]string
+
+// parseFilter parses a $filter expression such as "name eq 'subnet' and clusterReference eq '...'". Properties not
+// in allowed are rejected, as Prism Central does for properties that cannot be filtered on.
+func parseFilter(expression string, allowed ...string) (filter, error) {
+	parsed := filter{}
+	rest := strings.TrimSpace(expression)
+	for rest != "" {
+		property, afterProperty, ok := strings.Cut(rest, " eq ")
+		if !ok {
+			return nil, fmt.Errorf("expected a comparison with eq in %q", rest)
+		}
+		property = strings.TrimSpace(property)
+		isAllowed := false
+		for _, name := range allowed {
+			isAllowed = isAllowed || property == name
+		}
+		if !isAllowed {
+			return nil, fmt.Errorf("property %q cannot be filtered on", property)
+		}
+
+		value, afterValue, err := parseString(strings.TrimSpace(afterProperty))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of property %q: %w", property, err)
+		}
+		parsed[property] = value
+
+		rest = strings.TrimSpace(afterValue)
+		if rest == "" {
+			break
+		}
+		rest, ok = strings.CutPrefix(rest, "and ")
+		if !ok {
+			return nil, fmt.Errorf("expected and in %q", rest)
+		}
+	}
+	return parsed, nil
+}
+
+// parseString parses the string literal at the start of the expression, in which single quotes are escaped by
+// doubling them, returning the value and the rest of the expression.
+func parseString(expression string) (value, rest string, err error) {
+	if !strings.HasPrefix(expression, "'") {
+		return "", "", fmt.Errorf("expected a string literal in %q", expression)
+	}
+	var builder strings.Builder
+	for i := 1; i < len(expression); i++ {
+		if expression[i] != '\'' {
+			builder.WriteByte(expression[i])
+			continue
+		}
+		if i+1 < len(expression) && expression[i+1] == '\'' {
+			builder.WriteByte('\'')
+			i++
+			continue
+		}
+		return builder.String(), expression[i+1:], nil
+	}
+	return "", "", fmt.Errorf("unterminated string literal in %q", expression)
+}
+
+// parseFilterQuery parses the $filter query parameter of the request, writing a bad request error if it is invalid.
+func parseFilterQuery(w http.ResponseWriter, r *http.Request, allowed ...string) (filter, bool) {
+	parsed, err := parseFilter(r.URL.Query().Get("$filter"), allowed...)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "FAKE-400", "invalid $filter: %v", err)
+		return nil, false
+	}
+	return parsed, true
+}
+
+// matches returns whether the properties match all the comparisons of the filter.
+func (f filter) matches(properties map[string]string) bool {
+	for property, value := range f {
+		if properties[property] != value {
+			return false
+		}
+	}
+	return true
+}
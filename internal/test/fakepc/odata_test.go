@@ -0,0 +1,35 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package fakepc
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("parseFilter",
+	func(expression string, expected filter) {
+		Expect(parseFilter(expression, "name", "clusterReference")).To(Equal(expected))
+	},
+	Entry("no filter", "", filter{}),
+	Entry("single comparison", "name eq 'subnet-1'", filter{"name": "subnet-1"}),
+	Entry("multiple comparisons",
+		"name eq 'subnet-1' and clusterReference eq 'cluster'",
+		filter{"name": "subnet-1", "clusterReference": "cluster"}),
+	Entry("escaped quotes", "name eq 'O''Brien''s subnet'", filter{"name": "O'Brien's subnet"}),
+	Entry("injected comparison stays within the value",
+		"name eq 'x'' or name ne ''y'",
+		filter{"name": "x' or name ne 'y"}),
+)
+
+var _ = DescribeTable("parseFilter errors",
+	func(expression string) {
+		Expect(parseFilter(expression, "name")).Error().To(HaveOccurred())
+	},
+	Entry("unknown property", "vlanId eq '1'"),
+	Entry("unsupported operator", "name ne 'subnet-1'"),
+	Entry("unquoted value", "name eq subnet-1"),
+	Entry("unterminated value", "name eq 'subnet-1"),
+	Entry("unescaped quote", "name eq 'x' or name ne 'y'"),
+)
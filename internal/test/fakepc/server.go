@@ -0,0 +1,305 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fakepc implements an in-process fake of the Prism Central v4 APIs used by the IPAM provider, so that the
+// client can be exercised end to end without a Prism Central.
+//
+// The fake keeps its state in memory and implements:
+//   - session authentication with basic auth and a session cookie,
+//   - version negotiation,
+//   - listing and getting clusters and subnets, with OData filters on the name and cluster reference,
+//   - reserving IP addresses by count, range and list, and unreserving them by client context, range and list,
+//   - listing reserved IP addresses, with an OData filter on the client context,
+//   - polling the tasks of reservations and unreservations.
+//
+// Only IPv4 subnets are supported.
+package fakepc
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+	networkingerror "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/networking/v4/error"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// DefaultUsername is the username accepted by the server unless overridden with WithCredentials.
+	DefaultUsername = "admin"
+	// DefaultPassword is the password accepted by the server unless overridden with WithCredentials.
+	DefaultPassword = "Nutanix/4u"
+
+	// SessionCookie is the name of the cookie holding the session of an authenticated client.
+	SessionCookie = "NTNX_IAM_SESSION"
+
+	// APIVersion is the version of the v4 APIs reported by the server during version negotiation.
+	APIVersion = "v4.2"
+)
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithCredentials sets the username and password accepted by the server.
+func WithCredentials(username, password string) Option {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// WithTaskPolls sets the number of times a task is reported as running before it succeeds.
+func WithTaskPolls(polls int) Option {
+	return func(s *Server) {
+		s.taskPolls = polls
+	}
+}
+
+// Server is a fake Prism Central serving the v4 APIs over TLS on a local port.
+type Server struct {
+	server *httptest.Server
+
+	username  string
+	password  string
+	taskPolls int
+
+	mu       sync.Mutex
+	sessions map[string]struct{}
+	clusters map[uuid.UUID]*cluster
+	subnets  map[uuid.UUID]*subnet
+	tasks    map[uuid.UUID]*task
+	logins   int
+	requests int
+	failures []int
+}
+
+// NewServer starts a fake Prism Central. The server must be closed with Close once it is no longer used.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		username: DefaultUsername,
+		password: DefaultPassword,
+		sessions: map[string]struct{}{},
+		clusters: map[uuid.UUID]*cluster{},
+		subnets:  map[uuid.UUID]*subnet{},
+		tasks:    map[uuid.UUID]*task{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("OPTIONS /api/{namespace}/unversioned/info", s.getVersion)
+	mux.HandleFunc("GET /api/clustermgmt/{version}/config/clusters", s.listClusters)
+	mux.HandleFunc("GET /api/clustermgmt/{version}/config/clusters/{extId}", s.getCluster)
+	mux.HandleFunc("GET /api/networking/{version}/config/subnets", s.listSubnets)
+	mux.HandleFunc("GET /api/networking/{version}/config/subnets/{extId}", s.getSubnet)
+	mux.HandleFunc("GET /api/networking/{version}/config/subnets/{extId}/reserved-ips", s.listReservedIPs)
+	mux.HandleFunc("POST /api/networking/{version}/config/subnets/{extId}/addresses/$actions/reserve", s.reserveIPs)
+	mux.HandleFunc("POST /api/networking/{version}/config/subnets/{extId}/addresses/$actions/unreserve", s.unreserveIPs)
+	mux.HandleFunc("GET /api/prism/{version}/config/tasks/{extId}", s.getTask)
+
+	s.server = httptest.NewTLSServer(s.authenticate(mux))
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Endpoint returns the URL of the server, e.g. https://127.0.0.1:41234.
+func (s *Server) Endpoint() *url.URL {
+	endpoint, err := url.Parse(s.server.URL)
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse fake Prism Central URL %q: %v", s.server.URL, err))
+	}
+	return endpoint
+}
+
+// Host returns the host of the server.
+func (s *Server) Host() string {
+	return s.Endpoint().Hostname()
+}
+
+// Port returns the port of the server.
+func (s *Server) Port() int {
+	port, err := strconv.Atoi(s.Endpoint().Port())
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse fake Prism Central port %q: %v", s.Endpoint().Port(), err))
+	}
+	return port
+}
+
+// Certificate returns the self-signed certificate of the server, e.g. to build a trust bundle.
+func (s *Server) Certificate() *x509.Certificate {
+	return s.server.Certificate()
+}
+
+// Credentials returns the username and password accepted by the server.
+func (s *Server) Credentials() (username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.username, s.password
+}
+
+// SetCredentials changes the username and password accepted by the server and ends all sessions, e.g. to simulate
+// a password rotation.
+func (s *Server) SetCredentials(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username = username
+	s.password = password
+	clear(s.sessions)
+}
+
+// Logins returns the number of times a client authenticated with basic auth.
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// Requests returns the number of authenticated API requests served, including failed ones but excluding version
+// negotiation.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// FailRequests makes the next count authenticated API requests, excluding version negotiation, fail with the HTTP
+// status code, e.g. to simulate an unavailable Prism Central.
+func (s *Server) FailRequests(count, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for range count {
+		s.failures = append(s.failures, status)
+	}
+}
+
+// authenticate authenticates requests with the session cookie or basic auth, starting a session for the latter, and
+// injects the failures requested with FailRequests.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		authenticated := false
+		if cookie, err := r.Cookie(SessionCookie); err == nil {
+			_, authenticated = s.sessions[cookie.Value]
+		}
+		if username, password, ok := r.BasicAuth(); !authenticated && ok &&
+			username == s.username && password == s.password {
+			session := newSession()
+			s.sessions[session] = struct{}{}
+			s.logins++
+			authenticated = true
+			http.SetCookie(w, &http.Cookie{
+				Name:     SessionCookie,
+				Value:    session,
+				Path:     "/",
+				Secure:   true,
+				HttpOnly: true,
+			})
+		}
+		if !authenticated {
+			s.mu.Unlock()
+			writeError(w, http.StatusUnauthorized, "AUTH-401", "Authentication required")
+			return
+		}
+
+		if r.Method == http.MethodOptions {
+			s.mu.Unlock()
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s.requests++
+		status := 0
+		if len(s.failures) > 0 {
+			status, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if status != 0 {
+			writeError(w, status, fmt.Sprintf("FAKE-%d", status), "%s", http.StatusText(status))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newSession() string {
+	session := make([]byte, 16)
+	_, _ = rand.Read(session)
+	return hex.EncodeToString(session)
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"data": APIVersion})
+}
+
+// writeJSON writes the response with the status code.
+func writeJSON(w http.ResponseWriter, status int, response any) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// writeError writes a v4 error response with a single message.
+func writeError(w http.ResponseWriter, status int, code, format string, args ...any) {
+	errorResponse := networkingerror.NewErrorResponse()
+	message := networkingerror.NewAppMessage()
+	message.Code = ptr.To(code)
+	message.Message = ptr.To(fmt.Sprintf(format, args...))
+	if err := errorResponse.SetError([]networkingerror.AppMessage{*message}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, status, map[string]any{"data": errorResponse})
+}
+
+// parseExtID parses the extId path parameter, writing a not found error if it is not a UUID.
+func parseExtID(w http.ResponseWriter, r *http.Request, kind string) (uuid.UUID, bool) {
+	extID, err := uuid.Parse(r.PathValue("extId"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "FAKE-404", "%s %q not found", kind, r.PathValue("extId"))
+		return uuid.Nil, false
+	}
+	return extID, true
+}
+
+// page returns the page of the items selected by the $page and $limit query parameters.
+func page[T any](w http.ResponseWriter, r *http.Request, items []T) ([]T, bool) {
+	query := r.URL.Query()
+	pageNumber, limit := 0, 50
+	for name, value := range map[string]*int{"$page": &pageNumber, "$limit": &limit} {
+		if query.Get(name) == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(query.Get(name))
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "FAKE-400", "invalid %s %q", name, query.Get(name))
+			return nil, false
+		}
+		*value = parsed
+	}
+	if limit == 0 {
+		limit = 50
+	}
+
+	start := min(pageNumber*limit, len(items))
+	end := min(start+limit, len(items))
+	return items[start:end], true
+}
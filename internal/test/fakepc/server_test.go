@@ -0,0 +1,301 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package fakepc
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+	networkingapi "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/api"
+	networkingclient "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/client"
+	commonapi "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/common/v1/config"
+	networkingconfig "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/networking/v4/config"
+	prismapi "github.com/nutanix/ntnx-api-golang-clients/networking-go-client/v4/models/prism/v4/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go4.org/netipx"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		server          *Server
+		clusterExtID    uuid.UUID
+		subnetExtID     uuid.UUID
+		apiClient       *networkingclient.ApiClient
+		subnetsAPI      *networkingapi.SubnetsApi
+		reservationsAPI *networkingapi.SubnetIPReservationApi
+	)
+
+	BeforeEach(func() {
+		server = NewServer()
+		DeferCleanup(server.Close)
+
+		clusterExtID = server.AddCluster("cluster")
+		subnetExtID = server.AddSubnet(Subnet{
+			Name:       "subnet",
+			Cluster:    clusterExtID,
+			Prefix:     netip.MustParsePrefix("10.0.0.0/24"),
+			Gateway:    netip.MustParseAddr("10.0.0.1"),
+			Pools:      []netipx.IPRange{netipx.MustParseIPRange("10.0.0.10-10.0.0.12")},
+			DNSServers: []netip.Addr{netip.MustParseAddr("10.0.0.2")},
+		})
+
+		apiClient = networkingclient.NewApiClient()
+		apiClient.Host = server.Host()
+		apiClient.Port = server.Port()
+		apiClient.Username, apiClient.Password = server.Credentials()
+		apiClient.SetVerifySSL(false)
+		apiClient.MaxRetryAttempts = 1
+		apiClient.RetryInterval = time.Millisecond
+		subnetsAPI = networkingapi.NewSubnetsApi(apiClient)
+		reservationsAPI = networkingapi.NewSubnetIPReservationApi(apiClient)
+	})
+
+	listSubnets := func(filter string) []networkingconfig.Subnet {
+		GinkgoHelper()
+		response, err := subnetsAPI.ListSubnets(nil, nil, ptr.To(filter), nil, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		subnets, _ := response.GetData().([]networkingconfig.Subnet)
+		return subnets
+	}
+
+	reserve := func(spec *networkingconfig.IpReserveSpec) (string, error) {
+		GinkgoHelper()
+		response, err := reservationsAPI.ReserveIpsBySubnetId(ptr.To(subnetExtID.String()), spec)
+		if err != nil {
+			return "", err
+		}
+		taskReference, ok := response.GetData().(prismapi.TaskReference)
+		Expect(ok).To(BeTrue())
+		return ptr.Deref(taskReference.ExtId, ""), nil
+	}
+
+	getTask := func(extID string) apiTask {
+		GinkgoHelper()
+		taskURL := server.Endpoint().JoinPath("api/prism/v4.0/config/tasks", extID)
+		request, err := http.NewRequest(http.MethodGet, taskURL.String(), nil)
+		Expect(err).NotTo(HaveOccurred())
+		username, password := server.Credentials()
+		request.SetBasicAuth(username, password)
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+		response, err := httpClient.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(response.Body)
+		Expect(err).NotTo(HaveOccurred())
+		var task struct {
+			Data apiTask `json:"data"`
+		}
+		Expect(json.Unmarshal(body, &task)).To(Succeed())
+		return task.Data
+	}
+
+	It("should list and get subnets with OData filters", func() {
+		server.AddSubnet(Subnet{Name: "other-subnet", Prefix: netip.MustParsePrefix("10.1.0.0/24")})
+
+		subnets := listSubnets("name eq 'subnet' and clusterReference eq '" + clusterExtID.String() + "'")
+		Expect(subnets).To(HaveLen(1))
+		Expect(subnets[0].ExtId).To(HaveValue(Equal(subnetExtID.String())))
+		Expect(subnets[0].ClusterReference).To(HaveValue(Equal(clusterExtID.String())))
+		Expect(subnets[0].IpConfig).To(HaveLen(1))
+		Expect(subnets[0].IpConfig[0].Ipv4.IpSubnet.PrefixLength).To(HaveValue(Equal(24)))
+		Expect(subnets[0].IpConfig[0].Ipv4.DefaultGatewayIp.Value).To(HaveValue(Equal("10.0.0.1")))
+		Expect(subnets[0].IpConfig[0].Ipv4.PoolList).To(HaveLen(1))
+		Expect(subnets[0].IpUsage.NumFreeIPs).To(HaveValue(BeEquivalentTo(3)))
+
+		Expect(listSubnets("name eq 'other-subnet'")).To(HaveLen(1))
+		Expect(listSubnets("name eq 'subnet'' or name ne ''x'")).To(BeEmpty())
+
+		_, err := subnetsAPI.ListSubnets(nil, nil, ptr.To("vlanId eq '1'"), nil, nil, nil)
+		Expect(err).To(MatchError(ContainSubstring("400")))
+
+		response, err := subnetsAPI.GetSubnetById(ptr.To(subnetExtID.String()))
+		Expect(err).NotTo(HaveOccurred())
+		subnet, ok := response.GetData().(networkingconfig.Subnet)
+		Expect(ok).To(BeTrue())
+		Expect(subnet.Name).To(HaveValue(Equal("subnet")))
+
+		_, err = subnetsAPI.GetSubnetById(ptr.To(uuid.NewString()))
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+
+	It("should reserve IP addresses by count and complete the task", func() {
+		taskExtID, err := reserve(&networkingconfig.IpReserveSpec{
+			ReserveType:   ptr.To(networkingconfig.RESERVETYPE_IP_ADDRESS_COUNT),
+			Count:         ptr.To[int64](2),
+			ClientContext: ptr.To("claim-1"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.ReservedIPs(subnetExtID)).To(Equal(map[netip.Addr]string{
+			netip.MustParseAddr("10.0.0.10"): "claim-1",
+			netip.MustParseAddr("10.0.0.11"): "claim-1",
+		}))
+
+		task := getTask(taskExtID)
+		Expect(task.Status).To(Equal(TaskSucceeded))
+		Expect(task.CompletionDetails).To(ConsistOf(HaveField("Value", []string{"10.0.0.10", "10.0.0.11"})))
+
+		_, err = reserve(&networkingconfig.IpReserveSpec{
+			ReserveType: ptr.To(networkingconfig.RESERVETYPE_IP_ADDRESS_COUNT),
+			Count:       ptr.To[int64](2),
+		})
+		Expect(err).To(MatchError(ContainSubstring("Not enough free IPs")))
+	})
+
+	It("should report a task as running for the configured polls", func() {
+		server.Close()
+		server = NewServer(WithTaskPolls(1))
+		DeferCleanup(server.Close)
+		subnetExtID = server.AddSubnet(Subnet{
+			Name:   "subnet",
+			Prefix: netip.MustParsePrefix("10.0.0.0/24"),
+			Pools:  []netipx.IPRange{netipx.MustParseIPRange("10.0.0.10-10.0.0.12")},
+		})
+		apiClient.Host = server.Host()
+		apiClient.Port = server.Port()
+
+		taskExtID, err := reserve(&networkingconfig.IpReserveSpec{
+			ReserveType: ptr.To(networkingconfig.RESERVETYPE_IP_ADDRESS_COUNT),
+			Count:       ptr.To[int64](1),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getTask(taskExtID).Status).To(Equal(TaskRunning))
+		Expect(getTask(taskExtID).Status).To(Equal(TaskSucceeded))
+	})
+
+	It("should reserve IP addresses by range and list and reject addresses already reserved", func() {
+		_, err := reserve(&networkingconfig.IpReserveSpec{
+			ReserveType:    ptr.To(networkingconfig.RESERVETYPE_IP_ADDRESS_RANGE),
+			StartIpAddress: ipAddress("10.0.0.100"),
+			Count:          ptr.To[int64](2),
+			ClientContext:  ptr.To("claim-1"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = reserve(&networkingconfig.IpReserveSpec{
+			ReserveType:   ptr.To(networkingconfig.RESERVETYPE_IP_ADDRESS_LIST),
+			IpAddresses:   []commonapi.IPAddress{*ipAddress("10.0.0.50")},
+			ClientContext: ptr.To("claim-2"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = reserve(&networkingconfig.IpReserveSpec{
+			ReserveType: ptr.To(networkingconfig.RESERVETYPE_IP_ADDRESS_LIST),
+			IpAddresses: []commonapi.IPAddress{*ipAddress("10.0.0.60"), *ipAddress("10.0.0.101")},
+		})
+		Expect(err).To(MatchError(ContainSubstring("IP address 10.0.0.101 is already reserved")))
+
+		Expect(server.ReservedIPs(subnetExtID)).To(Equal(map[netip.Addr]string{
+			netip.MustParseAddr("10.0.0.100"): "claim-1",
+			netip.MustParseAddr("10.0.0.101"): "claim-1",
+			netip.MustParseAddr("10.0.0.50"):  "claim-2",
+		}))
+
+		response, err := reservationsAPI.ListReservedIpsBySubnetId(
+			ptr.To(subnetExtID.String()), nil, nil, ptr.To("clientContext eq 'claim-1'"), nil, nil,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		reservedIPs, _ := response.GetData().([]networkingconfig.ReservedIp)
+		Expect(reservedIPs).To(HaveLen(2))
+		Expect(reservedIPs[0].Ipv4Address).To(HaveValue(Equal("10.0.0.100")))
+		Expect(reservedIPs[0].ClientContext).To(HaveValue(Equal("claim-1")))
+	})
+
+	It("should list reserved IP addresses in pages", func() {
+		addrs := make([]netip.Addr, 0, 120)
+		for addr := netip.MustParseAddr("10.0.0.10"); len(addrs) < cap(addrs); addr = addr.Next() {
+			addrs = append(addrs, addr)
+		}
+		Expect(server.ReserveIPs(subnetExtID, "claim", addrs...)).To(Succeed())
+
+		listPage := func(page int) []networkingconfig.ReservedIp {
+			GinkgoHelper()
+			response, err := reservationsAPI.ListReservedIpsBySubnetId(
+				ptr.To(subnetExtID.String()), ptr.To(page), ptr.To(100), nil, nil, nil,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Metadata.TotalAvailableResults).To(HaveValue(Equal(120)))
+			reservedIPs, _ := response.GetData().([]networkingconfig.ReservedIp)
+			return reservedIPs
+		}
+
+		firstPage := listPage(0)
+		Expect(firstPage).To(HaveLen(100))
+		Expect(firstPage[0].Ipv4Address).To(HaveValue(Equal("10.0.0.10")))
+		secondPage := listPage(1)
+		Expect(secondPage).To(HaveLen(20))
+		Expect(secondPage[0].Ipv4Address).To(HaveValue(Equal("10.0.0.110")))
+		Expect(listPage(2)).To(BeEmpty())
+	})
+
+	It("should unreserve IP addresses by client context, range and list", func() {
+		Expect(server.ReserveIPs(subnetExtID, "claim-1", netip.MustParseAddr("10.0.0.10"))).To(Succeed())
+		Expect(server.ReserveIPs(subnetExtID, "claim-2",
+			netip.MustParseAddr("10.0.0.20"), netip.MustParseAddr("10.0.0.21"))).To(Succeed())
+		Expect(server.ReserveIPs(subnetExtID, "", netip.MustParseAddr("10.0.0.30"))).To(Succeed())
+
+		unreserve := func(spec *networkingconfig.IpUnreserveSpec) error {
+			_, err := reservationsAPI.UnreserveIpsBySubnetId(ptr.To(subnetExtID.String()), spec)
+			return err
+		}
+
+		Expect(unreserve(&networkingconfig.IpUnreserveSpec{
+			UnreserveType: ptr.To(networkingconfig.UNRESERVETYPE_CONTEXT),
+			ClientContext: ptr.To("claim-1"),
+		})).To(Succeed())
+		Expect(unreserve(&networkingconfig.IpUnreserveSpec{
+			UnreserveType: ptr.To(networkingconfig.UNRESERVETYPE_CONTEXT),
+			ClientContext: ptr.To("claim-1"),
+		})).To(MatchError(ContainSubstring("No IP addresses exist with context claim-1")))
+
+		Expect(unreserve(&networkingconfig.IpUnreserveSpec{
+			UnreserveType:  ptr.To(networkingconfig.UNRESERVETYPE_IP_ADDRESS_RANGE),
+			StartIpAddress: ipAddress("10.0.0.20"),
+			Count:          ptr.To[int64](2),
+		})).To(Succeed())
+		Expect(unreserve(&networkingconfig.IpUnreserveSpec{
+			UnreserveType: ptr.To(networkingconfig.UNRESERVETYPE_IP_ADDRESS_LIST),
+			IpAddresses:   []commonapi.IPAddress{*ipAddress("10.0.0.30")},
+		})).To(Succeed())
+
+		Expect(server.ReservedIPs(subnetExtID)).To(BeEmpty())
+	})
+
+	It("should reuse the session cookie after the first request", func() {
+		listSubnets("")
+		listSubnets("")
+		Expect(server.Logins()).To(Equal(1))
+		Expect(server.Requests()).To(Equal(2))
+	})
+
+	It("should reject invalid credentials", func() {
+		apiClient.Password = "wrong"
+
+		_, err := subnetsAPI.ListSubnets(nil, nil, nil, nil, nil, nil)
+		Expect(err).To(MatchError(ContainSubstring("401")))
+		Expect(server.Logins()).To(BeZero())
+	})
+
+	It("should fail requests on demand", func() {
+		server.FailRequests(1, http.StatusInternalServerError)
+
+		_, err := subnetsAPI.ListSubnets(nil, nil, nil, nil, nil, nil)
+		Expect(err).To(MatchError(ContainSubstring("500")))
+		Expect(listSubnets("")).To(HaveLen(1))
+	})
+})
+
+func ipAddress(addr string) *commonapi.IPAddress {
+	address := commonapi.NewIPAddress()
+	address.Ipv4 = commonapi.NewIPv4Address()
+	address.Ipv4.Value = ptr.To(addr)
+	return address
+}
@@ -0,0 +1,16 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package fakepc
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFakePC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fake Prism Central Suite")
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package fakepc
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ReservedIPsCompletionDetail is the name of the completion detail listing the IP addresses reserved by a task.
+	ReservedIPsCompletionDetail = "reservedIps"
+	// UnreservedIPsCompletionDetail is the name of the completion detail listing the IP addresses unreserved by a
+	// task.
+	UnreservedIPsCompletionDetail = "unreservedIps"
+
+	// TaskRunning is the status of a task that is still running.
+	TaskRunning = "RUNNING"
+	// TaskSucceeded is the status of a task that completed successfully.
+	TaskSucceeded = "SUCCEEDED"
+)
+
+// task is an asynchronous task of a reservation or unreservation. The reservation or unreservation is applied as
+// soon as the task is created, while the task is reported as running for the polls set with WithTaskPolls.
+type task struct {
+	extID            uuid.UUID
+	subnetExtID      uuid.UUID
+	completionDetail string
+	addrs            []netip.Addr
+	polls            int
+}
+
+// apiTask is the prism v4 representation of a task, limited to the properties used by the client.
+type apiTask struct {
+	ObjectType         string              `json:"$objectType"`
+	ExtID              string              `json:"extId"`
+	Status             string              `json:"status"`
+	ProgressPercentage int                 `json:"progressPercentage"`
+	CompletionDetails  []apiKVPair         `json:"completionDetails,omitempty"`
+	EntitiesAffected   []apiEntityAffected `json:"entitiesAffected,omitempty"`
+}
+
+type apiKVPair struct {
+	ObjectType string   `json:"$objectType"`
+	Name       string   `json:"name"`
+	Value      []string `json:"value"`
+}
+
+type apiEntityAffected struct {
+	ObjectType string `json:"$objectType"`
+	ExtID      string `json:"extId"`
+	Rel        string `json:"rel"`
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	extID, ok := parseExtID(w, r, "task")
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	t, ok := s.tasks[extID]
+	polls := 0
+	if ok {
+		t.polls++
+		polls = t.polls
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "PRI-404", "task %q not found", extID)
+		return
+	}
+
+	response := apiTask{
+		ObjectType:         "prism.v4.config.Task",
+		ExtID:              t.extID.String(),
+		Status:             TaskRunning,
+		ProgressPercentage: 50,
+		EntitiesAffected: []apiEntityAffected{{
+			ObjectType: "prism.v4.config.EntityReference",
+			ExtID:      t.subnetExtID.String(),
+			Rel:        "networking:config:subnet",
+		}},
+	}
+	if polls > s.taskPolls {
+		response.Status = TaskSucceeded
+		response.ProgressPercentage = 100
+		addrs := make([]string, 0, len(t.addrs))
+		for _, addr := range t.addrs {
+			addrs = append(addrs, addr.String())
+		}
+		response.CompletionDetails = []apiKVPair{{
+			ObjectType: "common.v1.config.KVPair",
+			Name:       t.completionDetail,
+			Value:      addrs,
+		}}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": response})
+}
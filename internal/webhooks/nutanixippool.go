@@ -0,0 +1,393 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhooks implements the admission webhooks for the NutanixIPPool and GlobalNutanixIPPool kinds.
+package webhooks
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	ipamv1 "sigs.k8s.io/cluster-api/api/ipam/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/clientcontext"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/index"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/poolutil"
+)
+
+// pool is implemented by the NutanixIPPool and GlobalNutanixIPPool kinds.
+type pool interface {
+	ctrlclient.Object
+	PoolSpec() *v1alpha1.NutanixIPPoolSpec
+}
+
+// poolWebhook defaults and validates NutanixIPPool and GlobalNutanixIPPool objects.
+type poolWebhook[T pool] struct {
+	// client is used to list the IPAddresses allocated from a pool via the poolRef index.
+	client ctrlclient.Reader
+	// apiReader is used to check that referenced secrets and configmaps exist without caching them.
+	apiReader ctrlclient.Reader
+	kind      string
+}
+
+var (
+	_ admission.Defaulter[*v1alpha1.NutanixIPPool]       = &poolWebhook[*v1alpha1.NutanixIPPool]{}
+	_ admission.Validator[*v1alpha1.NutanixIPPool]       = &poolWebhook[*v1alpha1.NutanixIPPool]{}
+	_ admission.Defaulter[*v1alpha1.GlobalNutanixIPPool] = &poolWebhook[*v1alpha1.GlobalNutanixIPPool]{}
+	_ admission.Validator[*v1alpha1.GlobalNutanixIPPool] = &poolWebhook[*v1alpha1.GlobalNutanixIPPool]{}
+)
+
+// +kubebuilder:webhook:path=/mutate-ipam-cluster-x-k8s-io-v1alpha1-nutanixippool,mutating=true,failurePolicy=fail,sideEffects=None,groups=ipam.cluster.x-k8s.io,resources=nutanixippools,verbs=create;update,versions=v1alpha1,name=default.nutanixippool.ipam.cluster.x-k8s.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-ipam-cluster-x-k8s-io-v1alpha1-nutanixippool,mutating=false,failurePolicy=fail,sideEffects=None,groups=ipam.cluster.x-k8s.io,resources=nutanixippools,verbs=create;update,versions=v1alpha1,name=validation.nutanixippool.ipam.cluster.x-k8s.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-ipam-cluster-x-k8s-io-v1alpha1-globalnutanixippool,mutating=true,failurePolicy=fail,sideEffects=None,groups=ipam.cluster.x-k8s.io,resources=globalnutanixippools,verbs=create;update,versions=v1alpha1,name=default.globalnutanixippool.ipam.cluster.x-k8s.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-ipam-cluster-x-k8s-io-v1alpha1-globalnutanixippool,mutating=false,failurePolicy=fail,sideEffects=None,groups=ipam.cluster.x-k8s.io,resources=globalnutanixippools,verbs=create;update,versions=v1alpha1,name=validation.globalnutanixippool.ipam.cluster.x-k8s.io,admissionReviewVersions=v1
+
+// SetupWebhooksWithManager sets up the NutanixIPPool and GlobalNutanixIPPool webhooks with the Manager.
+func SetupWebhooksWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr, &v1alpha1.NutanixIPPool{}).
+		WithDefaulter(newPoolWebhook[*v1alpha1.NutanixIPPool](mgr, v1alpha1.NutanixIPPoolKind)).
+		WithValidator(newPoolWebhook[*v1alpha1.NutanixIPPool](mgr, v1alpha1.NutanixIPPoolKind)).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up %s webhooks: %w", v1alpha1.NutanixIPPoolKind, err)
+	}
+
+	if err := ctrl.NewWebhookManagedBy(mgr, &v1alpha1.GlobalNutanixIPPool{}).
+		WithDefaulter(newPoolWebhook[*v1alpha1.GlobalNutanixIPPool](mgr, v1alpha1.GlobalNutanixIPPoolKind)).
+		WithValidator(newPoolWebhook[*v1alpha1.GlobalNutanixIPPool](mgr, v1alpha1.GlobalNutanixIPPoolKind)).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to set up %s webhooks: %w", v1alpha1.GlobalNutanixIPPoolKind, err)
+	}
+
+	return nil
+}
+
+func newPoolWebhook[T pool](mgr ctrl.Manager, kind string) *poolWebhook[T] {
+	return &poolWebhook[T]{
+		client:    mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
+		kind:      kind,
+	}
+}
+
+// Default canonicalizes the UUIDs and IP addresses in the pool spec, so that equivalent values are not reported
+// as spec changes.
+func (w *poolWebhook[T]) Default(_ context.Context, obj T) error {
+	spec := obj.PoolSpec()
+
+	spec.Subnet = canonicalUUID(spec.Subnet)
+	if spec.IPv6Subnet != nil {
+		spec.IPv6Subnet = ptr.To(canonicalUUID(*spec.IPv6Subnet))
+	}
+	if spec.Cluster != nil {
+		spec.Cluster = ptr.To(canonicalUUID(*spec.Cluster))
+	}
+	for i := range spec.FallbackSubnets {
+		fallback := &spec.FallbackSubnets[i]
+		fallback.Subnet = canonicalUUID(fallback.Subnet)
+		if fallback.Cluster != nil {
+			fallback.Cluster = ptr.To(canonicalUUID(*fallback.Cluster))
+		}
+	}
+	if spec.Gateway != nil {
+		if addr, err := netip.ParseAddr(*spec.Gateway); err == nil {
+			spec.Gateway = ptr.To(addr.String())
+		}
+	}
+
+	return nil
+}
+
+// canonicalUUID returns the canonical lower case form of the value if it is a UUID, otherwise the value is
+// returned unchanged.
+func canonicalUUID(value string) string {
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return value
+	}
+	return parsed.String()
+}
+
+// ValidateCreate validates the references of a new pool.
+func (w *poolWebhook[T]) ValidateCreate(ctx context.Context, obj T) (admission.Warnings, error) {
+	allErrs := w.validateReferences(ctx, obj, nil)
+	allErrs = append(allErrs, validateAddresses(obj.PoolSpec())...)
+	allErrs = append(allErrs, validateClientContextTemplate(obj.PoolSpec())...)
+	return nil, w.toInvalid(obj, allErrs)
+}
+
+// ValidateUpdate validates changed references of a pool, and rejects changes to the fields identifying the
+// subnets to allocate from while IPs are allocated from the pool.
+func (w *poolWebhook[T]) ValidateUpdate(ctx context.Context, oldObj, newObj T) (admission.Warnings, error) {
+	// Do not block the removal of finalizers from a pool that is being deleted.
+	if !newObj.GetDeletionTimestamp().IsZero() {
+		return nil, nil
+	}
+
+	allErrs := w.validateReferences(ctx, newObj, oldObj.PoolSpec())
+	allErrs = append(allErrs, validateAddresses(newObj.PoolSpec())...)
+	allErrs = append(allErrs, validateClientContextTemplate(newObj.PoolSpec())...)
+
+	immutableErrs, err := w.validateImmutableWhileInUse(ctx, oldObj, newObj)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	allErrs = append(allErrs, immutableErrs...)
+
+	return nil, w.toInvalid(newObj, allErrs)
+}
+
+// ValidateDelete allows all deletions. Deletion is blocked by the pool finalizer while IPs are allocated from the
+// pool.
+func (w *poolWebhook[T]) ValidateDelete(_ context.Context, _ T) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (w *poolWebhook[T]) toInvalid(obj T, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: v1alpha1.GroupVersion.Group, Kind: w.kind},
+		obj.GetName(),
+		allErrs,
+	)
+}
+
+// validateReferences validates the credentials secret and trust bundle referenced by the pool. If oldSpec is set,
+// only the references that changed are validated, so that a pool whose secret was deleted can still be updated.
+func (w *poolWebhook[T]) validateReferences(
+	ctx context.Context,
+	obj T,
+	oldSpec *v1alpha1.NutanixIPPoolSpec,
+) field.ErrorList {
+	var allErrs field.ErrorList
+
+	pcPath := field.NewPath("spec", "prismCentral")
+	pc := obj.PoolSpec().PrismCentral
+	var oldPC *v1alpha1.PrismCentral
+	if oldSpec != nil {
+		oldPC = &oldSpec.PrismCentral
+	}
+
+	if oldPC == nil || oldPC.CredentialsSecretRef != pc.CredentialsSecretRef {
+		secretPath := pcPath.Child("credentialsSecretRef")
+		key := ctrlclient.ObjectKey{
+			Namespace: v1alpha1.ReferenceNamespace(obj, pc.CredentialsSecretRef.Namespace),
+			Name:      pc.CredentialsSecretRef.Name,
+		}
+		if err := w.apiReader.Get(ctx, key, &corev1.Secret{}); err != nil {
+			allErrs = append(allErrs, referenceError(secretPath, key, "secret", err))
+		}
+	}
+
+	if pc.AdditionalTrustBundle == nil {
+		return allErrs
+	}
+	trustBundlePath := pcPath.Child("additionalTrustBundle")
+
+	if ref := pc.AdditionalTrustBundle.ConfigMapReference; ref != nil {
+		var oldRef *v1alpha1.LocalConfigMapRef
+		if oldPC != nil && oldPC.AdditionalTrustBundle != nil {
+			oldRef = oldPC.AdditionalTrustBundle.ConfigMapReference
+		}
+		if oldRef == nil || *oldRef != *ref {
+			key := ctrlclient.ObjectKey{
+				Namespace: v1alpha1.ReferenceNamespace(obj, ref.Namespace),
+				Name:      ref.Name,
+			}
+			if err := w.apiReader.Get(ctx, key, &corev1.ConfigMap{}); err != nil {
+				allErrs = append(
+					allErrs,
+					referenceError(trustBundlePath.Child("trustBundleConfigMapRef"), key, "configmap", err),
+				)
+			}
+		}
+	}
+
+	if len(pc.AdditionalTrustBundle.Data) > 0 {
+		if err := validatePEMCertificates(pc.AdditionalTrustBundle.Data); err != nil {
+			allErrs = append(allErrs, field.Invalid(
+				trustBundlePath.Child("trustBundleData"),
+				"<trust bundle data>",
+				err.Error(),
+			))
+		}
+	}
+
+	return allErrs
+}
+
+// validateAddresses validates that the allowed and excluded addresses of the pool are CIDRs, IP ranges or IPs.
+func validateAddresses(spec *v1alpha1.NutanixIPPoolSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	specPath := field.NewPath("spec")
+	for _, list := range []struct {
+		path      *field.Path
+		addresses []string
+	}{
+		{specPath.Child("allowedRanges"), spec.AllowedRanges},
+		{specPath.Child("excludedAddresses"), spec.ExcludedAddresses},
+	} {
+		for i, address := range list.addresses {
+			if _, err := poolutil.AddressToIPRange(address); err != nil {
+				allErrs = append(allErrs, field.Invalid(list.path.Index(i), address, err.Error()))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateClientContextTemplate validates that the client context template of the pool can be parsed.
+func validateClientContextTemplate(spec *v1alpha1.NutanixIPPoolSpec) field.ErrorList {
+	if spec.ClientContextTemplate == nil {
+		return nil
+	}
+	if _, err := clientcontext.Parse(*spec.ClientContextTemplate); err != nil {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "clientContextTemplate"),
+			*spec.ClientContextTemplate,
+			err.Error(),
+		)}
+	}
+	return nil
+}
+
+func referenceError(path *field.Path, key ctrlclient.ObjectKey, kind string, err error) *field.Error {
+	if apierrors.IsNotFound(err) {
+		return field.NotFound(path, key.String())
+	}
+	return field.InternalError(path, fmt.Errorf("failed to get %s %s: %w", kind, key, err))
+}
+
+// validatePEMCertificates returns an error unless data consists of one or more PEM encoded x509 certificates.
+func validatePEMCertificates(data []byte) error {
+	rest := data
+	count := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("unexpected PEM block of type %q, expected CERTIFICATE", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		count++
+	}
+
+	if strings.TrimSpace(string(rest)) != "" {
+		return errors.New("trust bundle contains data that is not PEM encoded")
+	}
+	if count == 0 {
+		return errors.New("trust bundle does not contain any PEM encoded certificates")
+	}
+	return nil
+}
+
+// validateImmutableWhileInUse rejects changes to the fields identifying the subnets to allocate from while IPs
+// are allocated from the pool, as those IPs could no longer be released.
+func (w *poolWebhook[T]) validateImmutableWhileInUse(
+	ctx context.Context,
+	oldObj, newObj T,
+) (field.ErrorList, error) {
+	oldSpec, newSpec := oldObj.PoolSpec(), newObj.PoolSpec()
+	specPath := field.NewPath("spec")
+
+	type change struct {
+		path     *field.Path
+		old, new any
+	}
+	// UUIDs are compared in their canonical form, as Default canonicalizes them in the new spec but not in the spec
+	// of pools stored before it did.
+	var changed []change
+	if canonicalUUID(oldSpec.Subnet) != canonicalUUID(newSpec.Subnet) {
+		changed = append(changed, change{specPath.Child("subnet"), oldSpec.Subnet, newSpec.Subnet})
+	}
+	if !equalUUIDPtr(oldSpec.IPv6Subnet, newSpec.IPv6Subnet) {
+		changed = append(changed, change{specPath.Child("ipv6Subnet"), oldSpec.IPv6Subnet, newSpec.IPv6Subnet})
+	}
+	if !equalUUIDPtr(oldSpec.Cluster, newSpec.Cluster) {
+		changed = append(changed, change{specPath.Child("cluster"), oldSpec.Cluster, newSpec.Cluster})
+	}
+	// Fallback subnets can be appended, e.g. once the pool is exhausted, but the existing ones cannot be changed
+	// or removed.
+	for i, oldFallback := range oldSpec.FallbackSubnets {
+		fallbackPath := specPath.Child("fallbackSubnets").Index(i)
+		if i >= len(newSpec.FallbackSubnets) {
+			changed = append(changed, change{fallbackPath, oldFallback, nil})
+			continue
+		}
+		newFallback := newSpec.FallbackSubnets[i]
+		if canonicalUUID(oldFallback.Subnet) != canonicalUUID(newFallback.Subnet) ||
+			!equalUUIDPtr(oldFallback.Cluster, newFallback.Cluster) {
+			changed = append(changed, change{fallbackPath, oldFallback, newFallback})
+		}
+	}
+	if oldSpec.PrismCentral.Address != newSpec.PrismCentral.Address {
+		changed = append(changed, change{
+			specPath.Child("prismCentral", "address"),
+			oldSpec.PrismCentral.Address,
+			newSpec.PrismCentral.Address,
+		})
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	addresses := &ipamv1.IPAddressList{}
+	if err := w.client.List(ctx, addresses,
+		ctrlclient.InNamespace(newObj.GetNamespace()),
+		ctrlclient.MatchingFields{
+			index.IPAddressPoolRefCombinedField: index.IPPoolRefValue(ipamv1.IPPoolReference{
+				Name:     newObj.GetName(),
+				Kind:     w.kind,
+				APIGroup: v1alpha1.GroupVersion.Group,
+			}),
+		},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list IPAddresses: %w", err)
+	}
+	if len(addresses.Items) == 0 {
+		return nil, nil
+	}
+
+	allErrs := make(field.ErrorList, 0, len(changed))
+	for _, c := range changed {
+		allErrs = append(allErrs, field.Forbidden(
+			c.path,
+			fmt.Sprintf(
+				"field cannot be changed while %d IPAddresses are allocated from the pool",
+				len(addresses.Items),
+			),
+		))
+	}
+	return allErrs, nil
+}
+
+// equalUUIDPtr returns whether both values are unset, or set to the same value once canonicalized.
+func equalUUIDPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return canonicalUUID(*a) == canonicalUUID(*b)
+}
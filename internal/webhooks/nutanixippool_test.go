@@ -0,0 +1,322 @@
+// Copyright 2026 Nutanix. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ipamv1 "sigs.k8s.io/cluster-api/api/ipam/v1beta2"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/api/v1alpha1"
+	"github.com/nutanix-cloud-native/cluster-api-ipam-provider-nutanix/internal/index"
+)
+
+var _ = Describe("NutanixIPPool webhook", func() {
+	const (
+		namespace = "test-ns"
+		poolName  = "test-pool"
+	)
+
+	var (
+		objects []ctrlclient.Object
+		pool    *v1alpha1.NutanixIPPool
+	)
+
+	newWebhook := func() *poolWebhook[*v1alpha1.NutanixIPPool] {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		utilruntime.Must(ipamv1.AddToScheme(scheme))
+		utilruntime.Must(v1alpha1.AddToScheme(scheme))
+
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objects...).
+			WithIndex(&ipamv1.IPAddress{}, index.IPAddressPoolRefCombinedField, index.IPAddressByCombinedPoolRef).
+			Build()
+
+		return &poolWebhook[*v1alpha1.NutanixIPPool]{
+			client:    c,
+			apiReader: c,
+			kind:      v1alpha1.NutanixIPPoolKind,
+		}
+	}
+
+	BeforeEach(func() {
+		objects = []ctrlclient.Object{
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-secret",
+					Namespace: namespace,
+				},
+			},
+		}
+		pool = &v1alpha1.NutanixIPPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      poolName,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.NutanixIPPoolSpec{
+				PrismCentral: v1alpha1.PrismCentral{
+					Address: "prism.example.com",
+					Port:    9440,
+					CredentialsSecretRef: v1alpha1.LocalSecretRef{
+						Name: "test-secret",
+					},
+				},
+				Subnet:  "test-subnet",
+				Cluster: ptr.To("test-cluster"),
+			},
+		}
+	})
+
+	Context("Default", func() {
+		It("should canonicalize UUIDs and IP addresses", func() {
+			pool.Spec.Subnet = "6A1A5B4E-2D3C-4F5A-9B8C-7D6E5F4A3B2C"
+			pool.Spec.Cluster = ptr.To("Test-Cluster")
+			pool.Spec.Gateway = ptr.To("2001:DB8:0:0::1")
+
+			Expect(newWebhook().Default(context.Background(), pool)).To(Succeed())
+			Expect(pool.Spec.Subnet).To(Equal("6a1a5b4e-2d3c-4f5a-9b8c-7d6e5f4a3b2c"))
+			Expect(pool.Spec.Cluster).To(HaveValue(Equal("Test-Cluster")))
+			Expect(pool.Spec.Gateway).To(HaveValue(Equal("2001:db8::1")))
+		})
+	})
+
+	Context("ValidateCreate", func() {
+		It("should accept a pool referencing an existing secret", func() {
+			_, err := newWebhook().ValidateCreate(context.Background(), pool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject a pool referencing a missing secret", func() {
+			pool.Spec.PrismCentral.CredentialsSecretRef.Name = "missing"
+
+			_, err := newWebhook().ValidateCreate(context.Background(), pool)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.prismCentral.credentialsSecretRef")))
+		})
+
+		It("should reject a pool referencing a missing trust bundle configmap", func() {
+			pool.Spec.PrismCentral.AdditionalTrustBundle = &v1alpha1.AdditionalTrustBundle{
+				ConfigMapReference: &v1alpha1.LocalConfigMapRef{Name: "missing"},
+			}
+
+			_, err := newWebhook().ValidateCreate(context.Background(), pool)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(
+				ContainSubstring("spec.prismCentral.additionalTrustBundle.trustBundleConfigMapRef"),
+			))
+		})
+
+		It("should accept a pool with a valid PEM trust bundle", func() {
+			pool.Spec.PrismCentral.AdditionalTrustBundle = &v1alpha1.AdditionalTrustBundle{
+				Data: newPEMCertificate(),
+			}
+
+			_, err := newWebhook().ValidateCreate(context.Background(), pool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject a pool with an invalid PEM trust bundle", func() {
+			pool.Spec.PrismCentral.AdditionalTrustBundle = &v1alpha1.AdditionalTrustBundle{
+				Data: []byte("not a certificate"),
+			}
+
+			_, err := newWebhook().ValidateCreate(context.Background(), pool)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.prismCentral.additionalTrustBundle.trustBundleData")))
+		})
+
+		It("should reject a pool with invalid allowed and excluded addresses", func() {
+			pool.Spec.AllowedRanges = []string{"10.0.0.0/24", "10.0.1.10-10.0.1.5"}
+			pool.Spec.ExcludedAddresses = []string{"10.0.0.1", "not-an-ip"}
+
+			_, err := newWebhook().ValidateCreate(context.Background(), pool)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(And(
+				ContainSubstring("spec.allowedRanges[1]"),
+				ContainSubstring("spec.excludedAddresses[1]"),
+				Not(ContainSubstring("spec.allowedRanges[0]")),
+				Not(ContainSubstring("spec.excludedAddresses[0]")),
+			)))
+		})
+
+		It("should accept a pool with a valid client context template", func() {
+			pool.Spec.ClientContextTemplate = ptr.To("{{ .ClusterName }}/{{ .Namespace }}/{{ .Name }}/{{ .UID }}")
+
+			Expect(newWebhook().ValidateCreate(context.Background(), pool)).Error().NotTo(HaveOccurred())
+		})
+
+		It("should reject a client context template that does not reference the UID", func() {
+			pool.Spec.ClientContextTemplate = ptr.To("{{ .Namespace }}/{{ .Name }}")
+
+			_, err := newWebhook().ValidateCreate(context.Background(), pool)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.clientContextTemplate")))
+		})
+	})
+
+	Context("ValidateUpdate", func() {
+		addAddress := func() {
+			objects = append(objects, &ipamv1.IPAddress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-address",
+					Namespace: namespace,
+				},
+				Spec: ipamv1.IPAddressSpec{
+					PoolRef: ipamv1.IPPoolReference{
+						APIGroup: v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.NutanixIPPoolKind,
+						Name:     poolName,
+					},
+					Address: "10.0.0.10",
+					Prefix:  ptr.To[int32](24),
+				},
+			})
+		}
+
+		It("should allow subnet changes while no addresses are allocated", func() {
+			newPool := pool.DeepCopy()
+			newPool.Spec.Subnet = "other-subnet"
+
+			_, err := newWebhook().ValidateUpdate(context.Background(), pool, newPool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject subnet, cluster and address changes while addresses are allocated", func() {
+			addAddress()
+
+			newPool := pool.DeepCopy()
+			newPool.Spec.Subnet = "other-subnet"
+			newPool.Spec.Cluster = nil
+			newPool.Spec.PrismCentral.Address = "other.example.com"
+
+			_, err := newWebhook().ValidateUpdate(context.Background(), pool, newPool)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(And(
+				ContainSubstring("spec.subnet"),
+				ContainSubstring("spec.cluster"),
+				ContainSubstring("spec.prismCentral.address"),
+			)))
+		})
+
+		It("should allow other changes while addresses are allocated", func() {
+			addAddress()
+
+			newPool := pool.DeepCopy()
+			newPool.Spec.PrismCentral.Port = 9441
+
+			_, err := newWebhook().ValidateUpdate(context.Background(), pool, newPool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should allow updates to a pool in use that was stored with non-canonical UUIDs", func() {
+			addAddress()
+			pool.Spec.Subnet = "{6A1A5B4E-2D3C-4F5A-9B8C-7D6E5F4A3B2C}"
+			pool.Spec.Cluster = ptr.To("0F1E2D3C-4B5A-6978-8796-A5B4C3D2E1F0")
+			pool.Spec.FallbackSubnets = []v1alpha1.NutanixIPPoolFallbackSubnet{
+				{Subnet: "7B2B6C5F-3E4D-5A6B-AC9D-8E7F6A5B4C3D"},
+			}
+
+			newPool := pool.DeepCopy()
+			newPool.Labels = map[string]string{"foo": "bar"}
+			newPool.Finalizers = []string{v1alpha1.ProtectPoolFinalizer}
+			webhook := newWebhook()
+			Expect(webhook.Default(context.Background(), newPool)).To(Succeed())
+
+			_, err := webhook.ValidateUpdate(context.Background(), pool, newPool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject changes to existing fallback subnets while addresses are allocated", func() {
+			addAddress()
+			pool.Spec.FallbackSubnets = []v1alpha1.NutanixIPPoolFallbackSubnet{
+				{Subnet: "fallback-1"},
+				{Subnet: "fallback-2", Cluster: ptr.To("test-cluster")},
+			}
+
+			newPool := pool.DeepCopy()
+			newPool.Spec.FallbackSubnets = []v1alpha1.NutanixIPPoolFallbackSubnet{
+				{Subnet: "fallback-1", Cluster: ptr.To("other-cluster")},
+			}
+
+			_, err := newWebhook().ValidateUpdate(context.Background(), pool, newPool)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(And(
+				ContainSubstring("spec.fallbackSubnets[0]"),
+				ContainSubstring("spec.fallbackSubnets[1]"),
+			)))
+		})
+
+		It("should allow appending fallback subnets while addresses are allocated", func() {
+			addAddress()
+			pool.Spec.FallbackSubnets = []v1alpha1.NutanixIPPoolFallbackSubnet{{Subnet: "fallback-1"}}
+
+			newPool := pool.DeepCopy()
+			newPool.Spec.FallbackSubnets = append(
+				newPool.Spec.FallbackSubnets, v1alpha1.NutanixIPPoolFallbackSubnet{Subnet: "fallback-2"},
+			)
+
+			_, err := newWebhook().ValidateUpdate(context.Background(), pool, newPool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not revalidate an unchanged secret reference", func() {
+			objects = nil
+
+			newPool := pool.DeepCopy()
+			newPool.Labels = map[string]string{"foo": "bar"}
+
+			_, err := newWebhook().ValidateUpdate(context.Background(), pool, newPool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should allow updates to a pool that is being deleted", func() {
+			addAddress()
+
+			newPool := pool.DeepCopy()
+			newPool.DeletionTimestamp = ptr.To(metav1.Now())
+			newPool.Spec.Subnet = "other-subnet"
+
+			_, err := newWebhook().ValidateUpdate(context.Background(), pool, newPool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
+
+func newPEMCertificate() []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test-ca"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -247,7 +247,7 @@ go-generate: ; $(info $(M) running go generate)
 	  crd:headerFile=hack/license-header.yaml.txt \
 		output:crd:dir=config/crd/bases
 	controller-gen paths="./..." \
-	  webhook:headerFile="hack/license-header.yaml.txt"
+	  webhook:headerFile="hack/license-header-2026.yaml.txt"
 	$(MAKE) golines
 
 .PHONY: go-mod-upgrade